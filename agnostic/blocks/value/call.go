@@ -0,0 +1,115 @@
+package value
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+)
+
+// Refers to the value returned by calling a function
+type Call struct {
+	isValueType
+	function  string
+	arguments []Any
+}
+
+// The name of the function being called
+func (c Call) Function() string {
+	return c.function
+}
+
+func (c Call) Arguments() []Any {
+	return c.arguments
+}
+
+func (c Call) IsMethodDependent() bool {
+	for _, argument := range c.arguments {
+		if argument.IsMethodDependent() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewCall(function string, arguments ...Any) Call {
+	return Call{
+		function:  function,
+		arguments: arguments,
+	}
+}
+
+// Refers to the value returned by calling a method of the model whose method
+// is being called
+type OwnMethodCall struct {
+	isValueType
+	isMethodDependent
+	method    string
+	arguments []Any
+}
+
+// The name of the method being called
+func (o OwnMethodCall) Method() string {
+	return o.method
+}
+
+func (o OwnMethodCall) Arguments() []Any {
+	return o.arguments
+}
+
+func NewOwnMethodCall(method string, arguments ...Any) OwnMethodCall {
+	return OwnMethodCall{
+		method:    method,
+		arguments: arguments,
+	}
+}
+
+// Refers to the value returned by calling a method of another model. For
+// example, a model that is stored in a field. The model type is used to look up
+// the method's parameters when checking the call. It's checked against the
+// type of the model value whenever that type can be worked out
+type MethodCall struct {
+	isValueType
+	model     Any         // The value that contains the model
+	modelType types.Model // Must be the type of the model value
+	method    string
+	arguments []Any
+}
+
+func (m MethodCall) Model() Any {
+	return m.model
+}
+
+func (m MethodCall) ModelType() types.Model {
+	return m.modelType
+}
+
+// The name of the method being called
+func (m MethodCall) Method() string {
+	return m.method
+}
+
+func (m MethodCall) Arguments() []Any {
+	return m.arguments
+}
+
+func (m MethodCall) IsMethodDependent() bool {
+	if m.model.IsMethodDependent() {
+		return true
+	}
+
+	for _, argument := range m.arguments {
+		if argument.IsMethodDependent() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewMethodCall(model Any, modelType types.Model, method string, arguments ...Any) MethodCall {
+	return MethodCall{
+		model:     model,
+		modelType: modelType,
+		method:    method,
+		arguments: arguments,
+	}
+}
@@ -0,0 +1,24 @@
+package value
+
+// One of the values of an enum
+type EnumValue struct {
+	isValueType
+	isMethodIndependent
+	enumName  string
+	valueName string
+}
+
+func (e EnumValue) EnumName() string {
+	return e.enumName
+}
+
+func (e EnumValue) ValueName() string {
+	return e.valueName
+}
+
+func NewEnumValue(enumName, valueName string) EnumValue {
+	return EnumValue{
+		enumName:  enumName,
+		valueName: valueName,
+	}
+}
@@ -14,8 +14,21 @@ const (
 	GreatThanOrEqualTo Operator = ">="
 	LessThan           Operator = "<"
 	LassThanOrEqualTo  Operator = "<="
+	And                Operator = "&&" // The right value is only evaluated if the left is true
+	Or                 Operator = "||" // The right value is only evaluated if the left is false
 )
 
 func (m Operator) Value() string {
 	return string(m)
 }
+
+type UnaryOperator string
+
+const (
+	Not    UnaryOperator = "!"
+	Negate UnaryOperator = "-"
+)
+
+func (u UnaryOperator) Value() string {
+	return string(u)
+}
@@ -0,0 +1,27 @@
+package value
+
+// A single value with an operator applied to it
+type Unary struct {
+	isValueType
+	value    Any
+	operator UnaryOperator
+}
+
+func (u Unary) Value() Any {
+	return u.value
+}
+
+func (u Unary) Operator() UnaryOperator {
+	return u.operator
+}
+
+func (u Unary) IsMethodDependent() bool {
+	return u.value.IsMethodDependent()
+}
+
+func NewUnary(operator UnaryOperator, value Any) Unary {
+	return Unary{
+		value:    value,
+		operator: operator,
+	}
+}
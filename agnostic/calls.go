@@ -0,0 +1,314 @@
+package agnostic
+
+import (
+	"fmt"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+// Keeps track of the parameters of every method and function along with every
+// call made to them. A call may refer to a method or function that hasn't been
+// declared yet so calls are only checked once all of the code has been added
+type CallChecker struct {
+	signatures map[string][]Field // Keyed by "<model name>.<method name>" or the function name
+	models     map[string][]Field // The fields of every model keyed by the model's name
+	calls      []call
+}
+
+type call struct {
+	location  string // Where in the agnostic code the call was made
+	modelName string // The model whose method the call was made in. Empty if it wasn't made in a method
+	scope     *Scope // The variables that could be used where the call was made
+	target    string // The key of the signature being called
+	arguments []value.Any
+	model     value.Any   // The model that a method is called on. Nil if the call isn't a value.MethodCall
+	modelType types.Model // The type that the model was said to have
+}
+
+func (c *CallChecker) signature(target string, parameters []Field) {
+	if c.signatures == nil {
+		c.signatures = make(map[string][]Field)
+	}
+
+	c.signatures[target] = parameters
+}
+
+func (c *CallChecker) Method(modelName, methodName string, parameters []Field) {
+	c.signature(modelName+"."+methodName, parameters)
+}
+
+func (c *CallChecker) Function(name string, parameters []Field) {
+	c.signature(name, parameters)
+}
+
+// Records the fields of a model so that the types of its fields are known
+func (c *CallChecker) Model(modelName string, fields []Field) {
+	if c.models == nil {
+		c.models = make(map[string][]Field)
+	}
+
+	c.models[modelName] = fields
+}
+
+// Records every call made within the value. The model name is the name of the
+// model whose method the value is in or empty if it isn't in a method. The
+// scope holds the variables that the value can use. It's nil if there aren't
+// any
+func (c *CallChecker) Calls(location, modelName string, scope *Scope, any value.Any) {
+	newCall := func(target string, arguments []value.Any) call {
+		return call{location: location, modelName: modelName, scope: scope, target: target, arguments: arguments}
+	}
+
+	switch v := any.(type) {
+	case value.Call:
+		c.calls = append(c.calls, newCall(v.Function(), v.Arguments()))
+		c.callsIn(location, modelName, scope, v.Arguments()...)
+	case value.OwnMethodCall:
+		// Outside of a method there's no model to call. That's reported when
+		// the value is resolved
+		if modelName != "" {
+			c.calls = append(c.calls, newCall(modelName+"."+v.Method(), v.Arguments()))
+		}
+		c.callsIn(location, modelName, scope, v.Arguments()...)
+	case value.MethodCall:
+		methodCall := newCall(v.ModelType().ModelName()+"."+v.Method(), v.Arguments())
+		methodCall.model = v.Model()
+		methodCall.modelType = v.ModelType()
+		c.calls = append(c.calls, methodCall)
+		c.callsIn(location, modelName, scope, v.Model())
+		c.callsIn(location, modelName, scope, v.Arguments()...)
+	case value.Array:
+		c.callsIn(location, modelName, scope, v.Elements()...)
+	case value.Map:
+		for _, element := range v.Elements() {
+			c.callsIn(location, modelName, scope, element.Key(), element.Value())
+		}
+	case value.OwnField:
+		c.callsIn(location, modelName, scope, v.Field())
+	case value.ModelField:
+		c.callsIn(location, modelName, scope, v.Field())
+	case value.ArrayElement:
+		c.callsIn(location, modelName, scope, v.Array(), v.Index())
+	case value.MapElement:
+		c.callsIn(location, modelName, scope, v.Map(), v.Key())
+	case value.Combined:
+		c.callsIn(location, modelName, scope, v.Left(), v.Right())
+	case value.Unary:
+		c.callsIn(location, modelName, scope, v.Value())
+	case value.IntToString:
+		c.callsIn(location, modelName, scope, v.IntValue())
+	}
+}
+
+func (c *CallChecker) callsIn(location, modelName string, scope *Scope, values ...value.Any) {
+	for _, v := range values {
+		c.Calls(location, modelName, scope, v)
+	}
+}
+
+// Returns the given errors along with an error for every call that doesn't
+// match the method or function it calls. The given list isn't modified
+func (c *CallChecker) Check(errors ErrorList) ErrorList {
+	checked := make(ErrorList, len(errors), len(errors)+len(c.calls))
+	copy(checked, errors)
+
+	for _, call := range c.calls {
+		if err := c.check(call); err != nil {
+			checked.Add(call.location, err)
+		}
+	}
+
+	return checked
+}
+
+func (c *CallChecker) check(call call) error {
+	if call.model != nil {
+		modelType := c.typeOf(call.model, call.modelName, call.scope)
+		if modelType != nil && modelType != call.modelType {
+			return fmt.Errorf("%q is called on a value of type %s", call.target, typeName(modelType))
+		}
+	}
+
+	parameters, ok := c.signatures[call.target]
+	if !ok {
+		return fmt.Errorf("call to undeclared method or function %q", call.target)
+	}
+
+	if len(call.arguments) != len(parameters) {
+		return fmt.Errorf("%q takes %d arguments but was called with %d", call.target, len(parameters), len(call.arguments))
+	}
+
+	for i, argument := range call.arguments {
+		if isLiteral(argument) {
+			if !isAssignable(argument, parameters[i].Type) {
+				return fmt.Errorf("argument %q of %q can't be a %T", parameters[i].Name, call.target, argument)
+			}
+
+			continue
+		}
+
+		argumentType := c.typeOf(argument, call.modelName, call.scope)
+		if argumentType != nil && argumentType != parameters[i].Type {
+			return fmt.Errorf("argument %q of %q can't be a value of type %s", parameters[i].Name, call.target, typeName(argumentType))
+		}
+	}
+
+	return nil
+}
+
+// Returns the type of the value or nil if it can't be worked out. That's the
+// case for the results of calls and for variables whose type isn't known
+func (c *CallChecker) typeOf(any value.Any, modelName string, scope *Scope) types.Any {
+	switch v := any.(type) {
+	case value.Int:
+		return types.BaseInt
+	case value.Float:
+		return types.BaseFloat64
+	case value.String, value.IntToString:
+		return types.BaseString
+	case value.Bool:
+		return types.BaseBool
+	case value.Array:
+		return types.NewArray(v.ElementType())
+	case value.Map:
+		return types.NewMap(v.KeyType(), v.ValueType())
+	case value.EnumValue:
+		return types.NewModel(v.EnumName())
+	case value.Id:
+		declared := scope.lookup(v.Name())
+		switch {
+		case declared == nil:
+			return nil
+		case declared.value != nil:
+			return c.typeOf(declared.value, modelName, declared.parent)
+		default:
+			return declared.valueType
+		}
+	case value.OwnField:
+		return c.fieldType(modelName, v.Field())
+	case value.ModelField:
+		if model, ok := c.typeOf(value.NewId(v.ModelName()), modelName, scope).(types.Model); ok {
+			return c.fieldType(model.ModelName(), v.Field())
+		}
+	case value.ArrayElement:
+		if array, ok := c.typeOf(v.Array(), modelName, scope).(types.Array); ok {
+			return array.Element()
+		}
+	case value.MapElement:
+		if mapType, ok := c.typeOf(v.Map(), modelName, scope).(types.Map); ok {
+			return mapType.Value()
+		}
+	case value.Unary:
+		if v.Operator() == value.Not {
+			return types.BaseBool
+		}
+
+		return c.typeOf(v.Value(), modelName, scope)
+	case value.Combined:
+		switch v.Operator() {
+		case value.Add, value.Subtract, value.Multiply, value.Divide, value.Modulo:
+			// A literal takes the type of the other side
+			if !isLiteral(v.Left()) {
+				return c.typeOf(v.Left(), modelName, scope)
+			}
+			if !isLiteral(v.Right()) {
+				return c.typeOf(v.Right(), modelName, scope)
+			}
+		default:
+			return types.BaseBool
+		}
+	}
+
+	return nil
+}
+
+// Returns the type of a field of the model or nil if it can't be worked out
+func (c *CallChecker) fieldType(modelName string, field value.Any) types.Any {
+	switch v := field.(type) {
+	case value.Id:
+		for _, modelField := range c.models[modelName] {
+			if modelField.Name == v.Name() {
+				return modelField.Type
+			}
+		}
+	case value.ArrayElement:
+		if array, ok := c.fieldType(modelName, v.Array()).(types.Array); ok {
+			return array.Element()
+		}
+	case value.MapElement:
+		if mapType, ok := c.fieldType(modelName, v.Map()).(types.Map); ok {
+			return mapType.Value()
+		}
+	}
+
+	return nil
+}
+
+func isLiteral(any value.Any) bool {
+	switch any.(type) {
+	case value.Int, value.Float, value.String, value.Bool, value.Null, value.EnumValue:
+		return true
+	default:
+		return false
+	}
+}
+
+// The name of the type as it's written in Go
+func typeName(any types.Any) string {
+	switch t := any.(type) {
+	case types.Base:
+		switch t {
+		case types.BaseInt:
+			return "int"
+		case types.BaseInt32:
+			return "int32"
+		case types.BaseInt64:
+			return "int64"
+		case types.BaseFloat32:
+			return "float32"
+		case types.BaseFloat64:
+			return "float64"
+		case types.BaseBool:
+			return "bool"
+		case types.BaseString:
+			return "string"
+		}
+	case types.Model:
+		return t.ModelName()
+	case types.Array:
+		return "[]" + typeName(t.Element())
+	case types.Map:
+		return "map[" + typeName(t.Key()) + "]" + typeName(t.Value())
+	case types.Pointer:
+		return "*" + typeName(t.Value())
+	}
+
+	return fmt.Sprintf("%T", any)
+}
+
+// Whether the literal can be passed as the given type
+func isAssignable(any value.Any, t types.Any) bool {
+	switch v := any.(type) {
+	case value.Int:
+		return t == types.BaseInt || t == types.BaseInt32 || t == types.BaseInt64 ||
+			t == types.BaseFloat32 || t == types.BaseFloat64
+	case value.Float:
+		return t == types.BaseFloat32 || t == types.BaseFloat64
+	case value.String:
+		return t == types.BaseString
+	case value.Bool:
+		return t == types.BaseBool
+	case value.Null:
+		switch t.(type) {
+		case types.Array, types.Map, types.Pointer:
+			return true
+		default:
+			return false
+		}
+	case value.EnumValue:
+		model, ok := t.(types.Model)
+		return ok && model.ModelName() == v.EnumName()
+	default:
+		return true
+	}
+}
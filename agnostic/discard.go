@@ -0,0 +1,70 @@
+package agnostic
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+// A body implementation that ignores all of the logic that it is given. This
+// is meant for implementations that only output declarations (such as schema
+// languages) and therefore have no representation for method bodies
+type DiscardBody struct{}
+
+func (d DiscardBody) Assign(assignee, assigned value.Any) {}
+
+func (d DiscardBody) Declare(name string, value value.Any) {}
+
+func (d DiscardBody) AppendValue(array, value value.Any) {}
+
+func (d DiscardBody) AppendArray(array, valueArray value.Any) {}
+
+func (d DiscardBody) RemoveValue(array, index value.Any) {}
+
+func (d DiscardBody) MapPut(mapValue, key, value value.Any) {}
+
+func (d DiscardBody) MapDelete(mapValue, key value.Any) {}
+
+func (d DiscardBody) ForEach(array value.Any, indexName, valueName string) BodyImplementation {
+	return d
+}
+
+func (d DiscardBody) ForEachMap(mapValue value.Any, keyName, valueName string) BodyImplementation {
+	return d
+}
+
+func (d DiscardBody) ForEachMapSorted(mapValue value.Any, keyType types.Base, keyName, valueName string) BodyImplementation {
+	return d
+}
+
+func (d DiscardBody) ForRange(indexName string, start, end value.Any) BodyImplementation {
+	return d
+}
+
+func (d DiscardBody) While(value value.Any) BodyImplementation {
+	return d
+}
+
+func (d DiscardBody) Break() {}
+
+func (d DiscardBody) Continue() {}
+
+func (d DiscardBody) Switch(value value.Any, cases ...value.Any) (caseBodies []BodyImplementation, defaultBody BodyImplementation) {
+	caseBodies = make([]BodyImplementation, 0, len(cases))
+	for range cases {
+		caseBodies = append(caseBodies, d)
+	}
+
+	return caseBodies, d
+}
+
+func (d DiscardBody) If(value value.Any) BodyImplementation {
+	return d
+}
+
+func (d DiscardBody) IfElse(value value.Any) (trueBody, falseBody BodyImplementation) {
+	return d, d
+}
+
+func (d DiscardBody) Return(value value.Any) {}
+
+func (d DiscardBody) Call(call value.Any) {}
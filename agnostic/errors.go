@@ -0,0 +1,78 @@
+package agnostic
+
+import (
+	"strings"
+)
+
+// An error that occurred while generating a specific part of the code
+type GenerationError struct {
+	// Where in the agnostic code the error occurred. For example,
+	// "TestModel.MapPut" for a method or "TestModel.items" for a field
+	Location string
+	Err      error
+}
+
+func (g GenerationError) Error() string {
+	return g.Location + ": " + g.Err.Error()
+}
+
+func (g GenerationError) Unwrap() error {
+	return g.Err
+}
+
+// An error in one part of a value. The path names that part starting from the
+// statement or field default that the value belongs to. For example,
+// "MapPut.key.left" is the left side of the key given to MapPut
+type ValueError struct {
+	Path string
+	Err  error
+}
+
+func (v ValueError) Error() string {
+	return v.Path + ": " + v.Err.Error()
+}
+
+func (v ValueError) Unwrap() error {
+	return v.Err
+}
+
+// Adds the name to the front of the path of the error. This is used as a value
+// is resolved so that errors in nested values keep their full path. Nil is
+// returned if the error is nil
+func InValue(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if valueError, ok := err.(ValueError); ok {
+		return ValueError{Path: name + "." + valueError.Path, Err: valueError.Err}
+	}
+
+	return ValueError{Path: name, Err: err}
+}
+
+// Every error that occurred during a generation run. Implementations collect
+// errors as code is added so that all problems can be reported at once
+type ErrorList []error
+
+func (e *ErrorList) Add(location string, err error) {
+	*e = append(*e, GenerationError{Location: location, Err: err})
+}
+
+// Returns the list as an error or nil if the list is empty
+func (e ErrorList) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (e ErrorList) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
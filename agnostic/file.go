@@ -0,0 +1,31 @@
+package agnostic
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// Creates or truncates the named file and writes the contents to it. The
+// contents are rendered before the file is touched so that an existing file is
+// left as it was if rendering fails
+func WriteFile(fileName string, contents io.WriterTo) error {
+	var buffer bytes.Buffer
+	_, err := contents.WriteTo(&buffer)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	_, err = buffer.WriteTo(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -3,11 +3,18 @@ package agnostic
 import (
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+	"io"
 )
 
 type Field struct {
 	Name string
 	Type types.Any
+
+	// The value that the field starts with when a new model is created. This
+	// is only used for model fields and must not depend on a method. If nil,
+	// the field starts with the default for its type. Arrays and maps start
+	// empty and models start as a newly created model
+	Default value.Any
 }
 
 // A file in an arbitrary programming language
@@ -15,10 +22,17 @@ type Implementation interface {
 	// Writes the current contents of the file to the given path. The path
 	// should exclude the extension which which will be added by the
 	// implementation
-	Write(fileName string)
+	Write(fileName string) error
+
+	// Writes the current contents of the file to the given writer. This
+	// allows the output to be kept in memory or sent somewhere other than
+	// the local file system
+	WriteTo(out io.Writer) (n int64, err error)
 
-	// Creates a new model
+	// Creates a new model along with a constructor that initializes each of
+	// its fields
 	// Go Code: type <name> struct { <fields> }
+	//			func New<name>() *<name> { return &<name>{ <field defaults> } }
 	Model(name string, fields ...Field)
 
 	// Create an enumerated value. These only support integer values which will
@@ -32,17 +46,30 @@ type Implementation interface {
 	Enum(name string, values ...string)
 
 	// Create a new method. A method is simply a function that runs under the
-	// context of a model and has direct access to its contents
+	// context of a model and has direct access to its contents. The method can
+	// be called using a value.OwnMethodCall or value.MethodCall
 	// Go Code: func (<first character of modelName> *<modelName>) <methodName>(<parameters>) { <body> }
 	Method(modelName, methodName string, parameters ...Field) BodyImplementation
 
 	// Create a new method that returns a single value. A method is simply a
 	// function that runs under the context of a model and has direct access to
-	// its contents
+	// its contents. The method can be called using a value.OwnMethodCall or
+	// value.MethodCall
 	// Go Code: func (<first character of modelName> *<modelName>) <methodName>(<parameters>) <returnType> { <body> }
 	ReturnMethod(modelName, methodName string, returnType types.Any, parameters ...Field) BodyImplementation
+
+	// Create a new function that isn't bound to any model. The function can
+	// be called from any other body using a value.Call. The return type
+	// should be nil if the function doesn't return a value
+	// Go Code: func <name>(<parameters>) <returnType> { <body> }
+	Function(name string, returnType types.Any, parameters ...Field) BodyImplementation
 }
 
+// Prefix of the names of variables that implementations generate on their own.
+// Names given to an implementation must not start with it so that they can't
+// collide with the generated ones
+const ReservedPrefix = "_agnostic"
+
 // An ordered set of logic that runs inside of a method.
 type BodyImplementation interface {
 	// Assigns the value at assigned to assignee
@@ -84,6 +111,44 @@ type BodyImplementation interface {
 	// the implementation that the value is not used
 	// Go Code: `for <indexName>, <valueName> := range <array> { <body> }
 	ForEach(array value.Any, indexName, valueName string) BodyImplementation
+	// Iterates through every entry of the given map. Key name and value name
+	// are to be variables containing the current key and value. An empty
+	// string for a name will indicate to the implementation that the value is
+	// not used. The order of iteration is not defined and may differ between
+	// runs
+	// Go Code: `for <keyName>, <valueName> := range <mapValue> { <body> }`
+	ForEachMap(mapValue value.Any, keyName, valueName string) BodyImplementation
+	// Iterates through every entry of the given map in ascending order of its
+	// keys. This is the same as ForEachMap except that the order is
+	// deterministic. The key type must be a number or string. The variables
+	// that hold the sorted keys are named with the ReservedPrefix
+	// Go Code: `_agnosticKeys := make([]<keyType>, 0, len(<mapValue>))
+	//			for _agnosticKey := range <mapValue> { _agnosticKeys = append(_agnosticKeys, _agnosticKey) }
+	//			sort.Slice(_agnosticKeys, func(i, j int) bool { return _agnosticKeys[i] < _agnosticKeys[j] })
+	//			for _, <keyName> := range _agnosticKeys { <valueName> := <mapValue>[<keyName>]; <body> }`
+	ForEachMapSorted(mapValue value.Any, keyType types.Base, keyName, valueName string) BodyImplementation
+
+	// Counts from start up to, but not including, end. Index name is to be a
+	// variable containing the current count. The end value is evaluated
+	// before each iteration
+	// Go Code: `for <indexName> := <start>; <indexName> < <end>; <indexName>++ { <body> }`
+	ForRange(indexName string, start, end value.Any) BodyImplementation
+	// Repeatedly executes the body for as long as the value is true
+	// Go Code: `for <value> { <body> }`
+	While(value value.Any) BodyImplementation
+	// Exits the innermost loop
+	// Go Code: `break`
+	Break()
+	// Skips the remainder of the current iteration of the innermost loop
+	// Go Code: `continue`
+	Continue()
+
+	// Executes the body of the first case that is equal to the value or the
+	// default body if there are none. A case body never falls through into the
+	// next case. Calling Break inside of a case body exits the switch rather
+	// than any surrounding loop
+	// Go Code: `switch <value> { case <case>: <case body> ... default: <default body> }`
+	Switch(value value.Any, cases ...value.Any) (caseBodies []BodyImplementation, defaultBody BodyImplementation)
 
 	// Executes the body if the value is true
 	// Go Code: `if <value> { <body> }
@@ -94,4 +159,9 @@ type BodyImplementation interface {
 
 	// returns a single value from the method
 	Return(value value.Any)
+
+	// Calls a method or function and ignores any value that it returns. The
+	// value must be a value.Call, value.OwnMethodCall, or value.MethodCall
+	// Go Code: `<call>`
+	Call(call value.Any)
 }
@@ -0,0 +1,67 @@
+package agnostic
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+// The variables that can be used at some point in a body. Declaring a variable
+// creates a new scope instead of modifying the existing one so that a scope can
+// be kept to check the code that was added while it was current
+type Scope struct {
+	parent    *Scope
+	name      string
+	valueType types.Any // Nil if the type is taken from the value
+	value     value.Any // The value that the variable is initialized to. Nil if the type isn't known
+}
+
+// Creates a scope that contains the parameters of a method or function
+func NewScope(parameters []Field) *Scope {
+	var scope *Scope
+	for _, parameter := range parameters {
+		scope = scope.Declare(parameter.Name, parameter.Type)
+	}
+
+	return scope
+}
+
+// Returns a scope with a variable of the given type added. The type may be nil
+// if it isn't known
+func (s *Scope) Declare(name string, valueType types.Any) *Scope {
+	return &Scope{parent: s, name: name, valueType: valueType}
+}
+
+// Returns a scope with a variable added whose type is the type of the value.
+// The type is only found when the code is checked since it may depend on
+// models that haven't been declared yet
+func (s *Scope) DeclareValue(name string, value value.Any) *Scope {
+	return &Scope{parent: s, name: name, value: value}
+}
+
+// Returns a scope with the variables of a loop added. The key is the index or
+// map key and has the given type, which may be nil if it isn't known. The value
+// is an element of the collection being looped over. Empty names are skipped.
+// The value is declared first so that its type is found outside of the loop
+func (s *Scope) DeclareLoopVariables(keyName string, keyType types.Any, valueName string, element value.Any) *Scope {
+	scope := s
+	if valueName != "" {
+		scope = scope.DeclareValue(valueName, element)
+	}
+	if keyName != "" {
+		scope = scope.Declare(keyName, keyType)
+	}
+
+	return scope
+}
+
+// Returns the scope that the variable was declared in or nil if it wasn't
+// declared
+func (s *Scope) lookup(name string) *Scope {
+	for scope := s; scope != nil; scope = scope.parent {
+		if scope.name == name {
+			return scope
+		}
+	}
+
+	return nil
+}
@@ -58,7 +58,10 @@ func main() {
 
 	println("Write test agnostic code")
 	suite.GenerateAgnostic(implementation)
-	implementation.Write("test/" + output)
+	err = implementation.Write("test/" + output)
+	if err != nil {
+		panic(err)
+	}
 
 	testImplementation, err := targets.CreateTestImplementation(implementationName, implementationArgs)
 	if err != nil {
@@ -67,7 +70,10 @@ func main() {
 
 	println("Writing test files")
 	suite.GenerateTests(testImplementation)
-	testImplementation.Write("test/" + output + testSuffix)
+	err = testImplementation.Write("test/" + output + testSuffix)
+	if err != nil {
+		panic(err)
+	}
 
 	println("Writing Limitations Report")
 	err = test.GenerateLimitationReport(LimitationsReportFilePath, removedCases)
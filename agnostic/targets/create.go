@@ -4,15 +4,21 @@ import (
 	"errors"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/targets/golang"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/targets/jsonschema"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/targets/protobuf"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/targets/typescript"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/test"
 )
 
 func CreateImplementation(name string, args map[string]string) (implementation agnostic.Implementation, err error) {
 	if name == "go" {
-		return golang.NewImplementation(args), nil
+		return golang.NewImplementation(args)
 	} else if name == "typescript" {
-		return typescript.NewImplementation(args), nil
+		return typescript.NewImplementation(args)
+	} else if name == "protobuf" {
+		return protobuf.NewImplementation(args)
+	} else if name == "jsonschema" {
+		return jsonschema.NewImplementation(args)
 	}
 
 	return nil, errors.New("No implementation found for \"" + name + "\"")
@@ -20,9 +26,9 @@ func CreateImplementation(name string, args map[string]string) (implementation a
 
 func CreateTestImplementation(name string, args map[string]string) (implementation test.Implementation, err error) {
 	if name == "go" {
-		return golang.NewTestImplementation(args), nil
+		return golang.NewTestImplementation(args)
 	} else if name == "typescript" {
-		return typescript.NewTestImplementation(args), nil
+		return typescript.NewTestImplementation(args)
 	}
 
 	return nil, errors.New("No test implementation found for \"" + name + "\"")
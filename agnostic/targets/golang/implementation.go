@@ -3,22 +3,44 @@
 package golang
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
 	. "github.com/dave/jennifer/jen"
+	"io"
+	"strconv"
 	"strings"
 )
 
 type Implementation struct {
-	packageName string
-	code        []Code
+	packageName  string
+	code         []Code
+	constructors []*constructor
+	enums        map[string]bool // Names of every enum that has been declared
+	calls        agnostic.CallChecker
+	errors       agnostic.ErrorList
+}
+
+// The New<name> function of a model. A field whose type is another model starts
+// as a new instance of that model unless it's an enum. An enum may be declared
+// after the model so the function is only generated once the file is written
+type constructor struct {
+	modelName         string
+	initializedFields Dict
+	modelFields       map[string]string // Field name to the name of its model type
+	code              *Statement
 }
 
 type BodyImplementation struct {
-	receiverName string
+	modelName    string          // Empty if the body isn't part of a method
+	receiverName string          // Empty if the body isn't part of a method
+	location     string          // Used to give context to errors
+	scope        *agnostic.Scope // The variables that can be used by the next statement
+	calls        *agnostic.CallChecker
+	errors       *agnostic.ErrorList
 	block        *Statement
 }
 
@@ -30,32 +52,94 @@ func (g *BodyImplementation) Add(c ...Code) {
 	g.block.Add(lines(c...))
 }
 
-func (g *Implementation) Write(fileName string) {
-	jenFile := NewFile(g.packageName)
-	jenFile.Add(lines(g.code...))
-	err := jenFile.Save(fileName + ".go")
-	if err != nil {
-		panic(err)
+func (g *Implementation) Write(fileName string) error {
+	return agnostic.WriteFile(fileName+".go", g)
+}
+
+func (g *Implementation) WriteTo(out io.Writer) (n int64, err error) {
+	if err = g.calls.Check(g.errors).Err(); err != nil {
+		return 0, err
+	}
+
+	for _, c := range g.constructors {
+		g.generateConstructor(c)
 	}
+
+	return render(g.packageName, g.code, out)
 }
 
 func (g *Implementation) Model(modelName string, fields ...agnostic.Field) {
 	modelStructFields := make([]Code, 0)
+	initializedFields := make(Dict)
+	modelFields := make(map[string]string)
 	for _, field := range fields {
-		modelStructFields = append(modelStructFields, Id(field.Name).Add(resolveType(field.Type)))
+		fieldType, err := resolveType(field.Type)
+		if err != nil {
+			g.errors.Add(modelName+"."+field.Name, err)
+			continue
+		}
+
+		modelStructFields = append(modelStructFields, Id(field.Name).Add(fieldType))
+
+		if field.Default != nil {
+			g.calls.Calls(modelName+"."+field.Name, "", nil, field.Default)
+		}
+
+		if model, ok := field.Type.(types.Model); ok && field.Default == nil {
+			modelFields[field.Name] = model.ModelName()
+			continue
+		}
+
+		initialValue, err := resolveInitialValue(field)
+		if err != nil {
+			g.errors.Add(modelName+"."+field.Name, err)
+		} else if initialValue != nil {
+			initializedFields[Id(field.Name)] = initialValue
+		}
 	}
 
+	g.calls.Model(modelName, fields)
+
+	c := &constructor{
+		modelName:         modelName,
+		initializedFields: initializedFields,
+		modelFields:       modelFields,
+		code:              Null(),
+	}
+	g.constructors = append(g.constructors, c)
+
 	g.Add(Type().Id(string(modelName)).Struct(modelStructFields...))
+	g.Add(c.code)
+}
+
+// Replaces the code of the constructor so that it's up to date with the enums
+// that have been declared
+func (g *Implementation) generateConstructor(c *constructor) {
+	fields := make(Dict, len(c.initializedFields)+len(c.modelFields))
+	for field, initialValue := range c.initializedFields {
+		fields[field] = initialValue
+	}
+	for field, modelName := range c.modelFields {
+		// Enums start as their zero value
+		if !g.enums[modelName] {
+			fields[Id(field)] = Op("*").Id("New" + modelName).Call()
+		}
+	}
+
+	*c.code = *Func().Id("New" + c.modelName).Params().Op("*").Id(c.modelName).Block(
+		Return(Op("&").Id(c.modelName).Values(fields)),
+	)
 }
 
 func (g *Implementation) Enum(name string, values ...string) {
+	g.enums[name] = true
 	g.Add(Type().Id(name).Int())
 
 	enumValues := make([]Code, 0)
 	for i, v := range values {
 		valueName := name + "_" + v
 		if i == 0 {
-			enumValues = append(enumValues, Id(valueName).Id(name).Op(":=").Iota())
+			enumValues = append(enumValues, Id(valueName).Id(name).Op("=").Iota())
 		} else {
 			enumValues = append(enumValues, Id(valueName))
 		}
@@ -65,68 +149,150 @@ func (g *Implementation) Enum(name string, values ...string) {
 }
 
 func (g *Implementation) Method(modelName, methodName string, parameters ...agnostic.Field) agnostic.BodyImplementation {
+	location := modelName + "." + methodName
 	receiverName := strings.ToLower(modelName[:1])
 	block := Null()
+	g.calls.Method(modelName, methodName, parameters)
 
-	parametersCode := make([]Code, 0)
-	for _, param := range parameters {
-		parametersCode = append(parametersCode, Id(param.Name).Add(resolveType(param.Type)))
-	}
-
-	g.Add(Func().Params(Id(receiverName).Op("*").Id(modelName)).Id(methodName).Params(parametersCode...).Block(block))
+	g.Add(Func().Params(Id(receiverName).Op("*").Id(modelName)).Id(methodName).Params(g.resolveParameters(location, parameters)...).Block(block))
 
 	return &BodyImplementation{
+		modelName:    modelName,
 		receiverName: receiverName,
+		location:     location,
+		scope:        agnostic.NewScope(parameters),
+		calls:        &g.calls,
+		errors:       &g.errors,
 		block:        block,
 	}
 }
 
 func (g *Implementation) ReturnMethod(modelName, methodName string, returnType types.Any, parameters ...agnostic.Field) agnostic.BodyImplementation {
+	location := modelName + "." + methodName
 	receiverName := strings.ToLower(modelName[:1])
 	block := Null()
+	g.calls.Method(modelName, methodName, parameters)
+
+	g.Add(Func().Params(Id(receiverName).Op("*").Id(modelName)).Id(methodName).Params(g.resolveParameters(location, parameters)...).Add(g.resolveType(location, returnType)).Block(block))
+
+	return &BodyImplementation{
+		modelName:    modelName,
+		receiverName: receiverName,
+		location:     location,
+		scope:        agnostic.NewScope(parameters),
+		calls:        &g.calls,
+		errors:       &g.errors,
+		block:        block,
+	}
+}
+
+func (g *Implementation) Function(name string, returnType types.Any, parameters ...agnostic.Field) agnostic.BodyImplementation {
+	block := Null()
+	g.calls.Function(name, parameters)
+
+	function := Func().Id(name).Params(g.resolveParameters(name, parameters)...)
+	if returnType != nil {
+		function.Add(g.resolveType(name, returnType))
+	}
 
-	parametersCode := make([]Code, 0)
+	g.Add(function.Block(block))
+
+	return &BodyImplementation{
+		location: name,
+		scope:    agnostic.NewScope(parameters),
+		calls:    &g.calls,
+		errors:   &g.errors,
+		block:    block,
+	}
+}
+
+func (g *Implementation) resolveParameters(location string, parameters []agnostic.Field) []Code {
+	parametersCode := make([]Code, 0, len(parameters))
 	for _, param := range parameters {
-		parametersCode = append(parametersCode, Id(param.Name).Add(resolveType(param.Type)))
+		parametersCode = append(parametersCode, Id(param.Name).Add(g.resolveType(location, param.Type)))
 	}
 
-	g.Add(Func().Params(Id(receiverName).Op("*").Id(modelName)).Id(methodName).Params(parametersCode...).Add(resolveType(returnType)).Block(block))
+	return parametersCode
+}
 
+// Resolves the type while recording any error against the given location
+func (g *Implementation) resolveType(location string, any types.Any) *Statement {
+	resolved, err := resolveType(any)
+	if err != nil {
+		g.errors.Add(location, err)
+		return Null()
+	}
+
+	return resolved
+}
+
+// Creates a body that runs inside of this one. For example, the body of an if
+// statement
+func (g *BodyImplementation) child(block *Statement) *BodyImplementation {
 	return &BodyImplementation{
-		receiverName: receiverName,
+		modelName:    g.modelName,
+		receiverName: g.receiverName,
+		location:     g.location,
+		scope:        g.scope,
+		calls:        g.calls,
+		errors:       g.errors,
 		block:        block,
 	}
 }
 
+// Resolves the value while recording any error against this body's location.
+// The path names the value within the statement that uses it
+func (g *BodyImplementation) resolveValue(path string, any value.Any) *Statement {
+	if g.receiverName == "" && any.IsMethodDependent() {
+		g.errors.Add(g.location, agnostic.InValue(path, errors.New("method dependent value used outside of a method")))
+		return Null()
+	}
+
+	g.calls.Calls(g.location, g.modelName, g.scope, any)
+
+	resolved, err := resolveValue(any, g)
+	if err != nil {
+		g.errors.Add(g.location, agnostic.InValue(path, err))
+		return Null()
+	}
+
+	return resolved
+}
+
 func (g *BodyImplementation) Assign(assignee, assigned value.Any) {
-	g.Add(resolveValue(assignee, g).Op("=").Add(resolveValue(assigned, g)))
+	g.Add(g.resolveValue("Assign.assignee", assignee).Op("=").Add(g.resolveValue("Assign.assigned", assigned)))
 }
 
 func (g *BodyImplementation) Declare(name string, value value.Any) {
-	g.Add(Id(name).Op(":=").Add(resolveValue(value, g)))
+	g.Add(Id(name).Op(":=").Add(g.resolveValue("Declare.value", value)))
+	g.scope = g.scope.DeclareValue(name, value)
 }
 
 func (g *BodyImplementation) AppendValue(array, value value.Any) {
-	g.Add(resolveValue(array, g).Op("=").Append(resolveValue(array, g), resolveValue(value, g)))
+	resolvedArray := g.resolveValue("AppendValue.array", array)
+	g.Add(Add(resolvedArray).Op("=").Append(resolvedArray, g.resolveValue("AppendValue.value", value)))
 }
 
 func (g *BodyImplementation) AppendArray(array, valueArray value.Any) {
-	g.Add(resolveValue(array, g).Op("=").Append(resolveValue(array, g), resolveValue(valueArray, g).Op("...")))
+	resolvedArray := g.resolveValue("AppendArray.array", array)
+	g.Add(Add(resolvedArray).Op("=").Append(resolvedArray, g.resolveValue("AppendArray.valueArray", valueArray).Op("...")))
 }
 
 func (g *BodyImplementation) RemoveValue(array, index value.Any) {
-	g.Add(resolveValue(array, g).Op("=").Append(
-		resolveValue(array, g).Index(Op(":").Add(resolveValue(index, g))),
-		resolveValue(array, g).Index(resolveValue(index, g).Op("+").Lit(1).Op(":")).Op("..."),
+	resolvedArray := g.resolveValue("RemoveValue.array", array)
+	resolvedIndex := g.resolveValue("RemoveValue.index", index)
+	g.Add(Add(resolvedArray).Op("=").Append(
+		Add(resolvedArray).Index(Op(":").Add(resolvedIndex)),
+		Add(resolvedArray).Index(Add(resolvedIndex).Op("+").Lit(1).Op(":")).Op("..."),
 	))
 }
 
 func (g *BodyImplementation) MapPut(mapValue, key, value value.Any) {
-	g.Add(resolveValue(mapValue, g).Index(resolveValue(key, g)).Op("=").Add(resolveValue(value, g)))
+	g.Add(g.resolveValue("MapPut.mapValue", mapValue).Index(g.resolveValue("MapPut.key", key)).Op("=").Add(g.resolveValue("MapPut.value", value)))
 }
 
 func (g *BodyImplementation) MapDelete(mapValue, key value.Any) {
-	g.Add(Delete(resolveValue(mapValue, g), resolveValue(key, g)))
+	g.Add(Delete(g.resolveValue("MapDelete.mapValue", mapValue), g.resolveValue("MapDelete.key", key)))
 }
 
 func (g *BodyImplementation) ForEach(array value.Any, indexName, valueName string) agnostic.BodyImplementation {
@@ -146,146 +312,497 @@ func (g *BodyImplementation) ForEach(array value.Any, indexName, valueName strin
 	}
 
 	block := Null()
-	g.Add(For(forLoopParameter.Range().Add(resolveValue(array, g))).Block(block))
-	return &BodyImplementation{
-		receiverName: g.receiverName,
-		block:        block,
+	g.Add(For(forLoopParameter.Range().Add(g.resolveValue("ForEach.array", array))).Block(block))
+
+	body := g.child(block)
+	body.scope = body.scope.DeclareLoopVariables(indexName, types.BaseInt, valueName, value.NewArrayElement(array, value.NewId(indexName)))
+	return body
+}
+
+func (g *BodyImplementation) ForEachMap(mapValue value.Any, keyName, valueName string) agnostic.BodyImplementation {
+	var forLoopParameter *Statement
+	if keyName == "" {
+		if valueName == "" {
+			forLoopParameter = List()
+		} else {
+			forLoopParameter = List(Id("_"), Id(valueName)).Op(":=")
+		}
+	} else {
+		if valueName == "" {
+			forLoopParameter = List(Id(keyName)).Op(":=")
+		} else {
+			forLoopParameter = List(Id(keyName), Id(valueName)).Op(":=")
+		}
 	}
+
+	block := Null()
+	g.Add(For(forLoopParameter.Range().Add(g.resolveValue("ForEachMap.mapValue", mapValue))).Block(block))
+
+	body := g.child(block)
+	body.scope = body.scope.DeclareLoopVariables(keyName, nil, valueName, value.NewMapElement(mapValue, value.NewId(keyName)))
+	return body
 }
 
-func (g *BodyImplementation) If(value value.Any) agnostic.BodyImplementation {
+// Go doesn't define the order of map iteration so the keys are collected and
+// sorted before iterating. This is done in its own block so that the slice of
+// keys doesn't leak into the surrounding scope
+func (g *BodyImplementation) ForEachMapSorted(mapValue value.Any, keyType types.Base, keyName, valueName string) agnostic.BodyImplementation {
+	resolvedKeyType, err := resolveBaseType(keyType)
+	if err == nil && keyType == types.BaseBool {
+		err = errors.New("map keys of type bool can't be sorted")
+	}
+	if err != nil {
+		g.errors.Add(g.location, err)
+		return g.child(Null())
+	}
+
+	// The keys are collected with generated names so that they can't hide any
+	// of the variables that the map value or the body refer to
+	keys := agnostic.ReservedPrefix + "Keys"
+	collectedKey := agnostic.ReservedPrefix + "Key"
+	key := keyName
+	if key == "" {
+		key = collectedKey
+	}
+	resolvedMap := g.resolveValue("ForEachMapSorted.mapValue", mapValue)
+
+	var forLoopParameter *Statement
+	if keyName == "" && valueName == "" {
+		forLoopParameter = List()
+	} else {
+		forLoopParameter = List(Id("_"), Id(key)).Op(":=")
+	}
+
 	block := Null()
-	g.Add(If(resolveValue(value, g)).Block(block))
+	body := g.child(block)
+	body.scope = body.scope.DeclareLoopVariables(keyName, keyType, valueName, value.NewMapElement(mapValue, value.NewId(key)))
+	if valueName != "" {
+		body.Add(Id(valueName).Op(":=").Add(resolvedMap).Index(Id(key)))
+	}
 
-	return &BodyImplementation{
-		receiverName: g.receiverName,
-		block:        block,
+	g.Add(Block(
+		Id(keys).Op(":=").Make(Index().Add(resolvedKeyType), Lit(0), Len(resolvedMap)),
+		For(Id(collectedKey).Op(":=").Range().Add(resolvedMap)).Block(
+			Id(keys).Op("=").Append(Id(keys), Id(collectedKey)),
+		),
+		Qual("sort", "Slice").Call(Id(keys), Func().Params(Id("i"), Id("j").Int()).Bool().Block(
+			Return(Id(keys).Index(Id("i")).Op("<").Id(keys).Index(Id("j"))),
+		)),
+		For(forLoopParameter.Range().Id(keys)).Block(block),
+	))
+
+	return body
+}
+
+func (g *BodyImplementation) ForRange(indexName string, start, end value.Any) agnostic.BodyImplementation {
+	block := Null()
+	g.Add(For(
+		Id(indexName).Op(":=").Add(g.resolveValue("ForRange.start", start)),
+		Id(indexName).Op("<").Add(g.resolveValue("ForRange.end", end)),
+		Id(indexName).Op("++"),
+	).Block(block))
+
+	body := g.child(block)
+	body.scope = body.scope.Declare(indexName, types.BaseInt)
+	return body
+}
+
+func (g *BodyImplementation) While(value value.Any) agnostic.BodyImplementation {
+	block := Null()
+	g.Add(For(g.resolveValue("While.value", value)).Block(block))
+
+	return g.child(block)
+}
+
+func (g *BodyImplementation) Break() {
+	g.Add(Break())
+}
+
+func (g *BodyImplementation) Continue() {
+	g.Add(Continue())
+}
+
+func (g *BodyImplementation) Switch(value value.Any, cases ...value.Any) (caseBodies []agnostic.BodyImplementation, defaultBody agnostic.BodyImplementation) {
+	clauses := make([]Code, 0, len(cases)+1)
+	caseBodies = make([]agnostic.BodyImplementation, 0, len(cases))
+	for i, c := range cases {
+		block := Null()
+		clauses = append(clauses, Case(g.resolveValue("Switch.cases["+strconv.Itoa(i)+"]", c)).Block(block))
+		caseBodies = append(caseBodies, g.child(block))
 	}
+
+	defaultBlock := Null()
+	clauses = append(clauses, Default().Block(defaultBlock))
+
+	g.Add(Switch(g.resolveValue("Switch.value", value)).Block(clauses...))
+	return caseBodies, g.child(defaultBlock)
+}
+
+func (g *BodyImplementation) If(value value.Any) agnostic.BodyImplementation {
+	block := Null()
+	g.Add(If(g.resolveValue("If.value", value)).Block(block))
+
+	return g.child(block)
 }
 
 func (g *BodyImplementation) IfElse(value value.Any) (trueBody, falseBody agnostic.BodyImplementation) {
 	trueBlock, falseBlock := Null(), Null()
-	g.Add(If(resolveValue(value, g)).Block(trueBlock).Else().Block(falseBlock))
+	g.Add(If(g.resolveValue("IfElse.value", value)).Block(trueBlock).Else().Block(falseBlock))
 
-	trueBodyImplementation := &BodyImplementation{
-		receiverName: g.receiverName,
-		block:        trueBlock,
-	}
-	falseBodyImplementation := &BodyImplementation{
-		receiverName: g.receiverName,
-		block:        falseBlock,
-	}
+	return g.child(trueBlock), g.child(falseBlock)
+}
 
-	return trueBodyImplementation, falseBodyImplementation
+func (g *BodyImplementation) Call(call value.Any) {
+	switch call.(type) {
+	case value.Call, value.OwnMethodCall, value.MethodCall:
+		g.Add(g.resolveValue("Call.call", call))
+	default:
+		g.errors.Add(g.location, fmt.Errorf("%T can't be used as a statement", call))
+	}
 }
 
 func (g *BodyImplementation) Return(value value.Any) {
-	g.Add(Return(resolveValue(value, g)))
+	g.Add(Return(g.resolveValue("Return.value", value)))
 }
 
-func NewImplementation(args map[string]string) agnostic.Implementation {
+func NewImplementation(args map[string]string) (agnostic.Implementation, error) {
 	packageName, ok := args["package"]
 	if !ok {
-		panic(errors.New("no package name supplied"))
+		return nil, errors.New("no package name supplied")
 	}
 
 	return &Implementation{
 		code:        make([]Code, 0),
 		packageName: packageName,
-	}
+		enums:       make(map[string]bool),
+	}, nil
 }
 
-func resolveType(any types.Any) *Statement {
+func resolveType(any types.Any) (*Statement, error) {
 	switch t := any.(type) {
 	case types.Base:
 		return resolveBaseType(t)
 	case types.Model:
-		return Id(t.ModelName())
+		return Id(t.ModelName()), nil
 	case types.Array:
-		return Index().Add(resolveType(t.Element()))
+		element, err := resolveType(t.Element())
+		if err != nil {
+			return nil, err
+		}
+
+		return Index().Add(element), nil
 	case types.Map:
-		return Map(resolveType(t.Key())).Add(resolveType(t.Value()))
+		key, err := resolveType(t.Key())
+		if err != nil {
+			return nil, err
+		}
+
+		mapValue, err := resolveType(t.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		return Map(key).Add(mapValue), nil
 	case types.Pointer:
-		return Op("*").Add(resolveType(t.Value()))
+		pointedTo, err := resolveType(t.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		return Op("*").Add(pointedTo), nil
 	default:
-		panic(errors.New(fmt.Sprintf("unkown type %T", t)))
+		return nil, fmt.Errorf("unsupported type %T in go", t)
 	}
 }
 
-func resolveBaseType(base types.Base) *Statement {
+func resolveBaseType(base types.Base) (*Statement, error) {
 	switch base {
 	case types.BaseBool:
-		return Bool()
+		return Bool(), nil
 	case types.BaseInt:
-		return Int()
+		return Int(), nil
 	case types.BaseInt32:
-		return Int32()
+		return Int32(), nil
 	case types.BaseInt64:
-		return Int64()
+		return Int64(), nil
 	case types.BaseFloat32:
-		return Float32()
+		return Float32(), nil
 	case types.BaseFloat64:
-		return Float64()
+		return Float64(), nil
 	case types.BaseString:
-		return String()
+		return String(), nil
+	default:
+		return nil, errors.New("unsupported base type " + strconv.Itoa(int(base)) + " in go")
+	}
+}
+
+// Resolves the value that a field starts with in a newly created model. Nil is
+// returned if the field can be left as Go's zero value. Fields whose type is a
+// model are handled by the constructor
+func resolveInitialValue(field agnostic.Field) (*Statement, error) {
+	if field.Default != nil {
+		resolved, err := resolveValue(field.Default, nil)
+		return resolved, agnostic.InValue("Default", err)
+	}
+
+	switch t := field.Type.(type) {
+	case types.Array, types.Map:
+		fieldType, err := resolveType(t)
+		if err != nil {
+			return nil, err
+		}
+
+		return fieldType.Values(), nil
 	default:
-		panic(errors.New("unknown base type " + string(base)))
+		return nil, nil
 	}
 }
 
-// Convert a value interface into its representation into Go code form
-func resolveValue(any value.Any, optionalContext ...*BodyImplementation) *Statement {
-	var context *BodyImplementation
-	if len(optionalContext) == 0 && any.IsMethodDependent() {
-		panic(errors.New("no context provided for method dependent value"))
-	} else if len(optionalContext) == 1 {
-		context = optionalContext[0]
-	} else if len(optionalContext) > 1 {
-		panic(errors.New("multiple body contexts provided when only 0 or 1 is allowed"))
+// Convert a value interface into its representation into Go code form. The
+// context is the body that the value is used in or nil if it isn't used in a
+// method
+func resolveValue(any value.Any, context *BodyImplementation) (*Statement, error) {
+	if (context == nil || context.receiverName == "") && any.IsMethodDependent() {
+		return nil, errors.New("method dependent value used outside of a method")
 	}
 
 	switch v := any.(type) {
 	case value.Null:
-		return Nil()
+		return Nil(), nil
 	case value.String:
-		return Lit(v.Value())
+		return Lit(v.Value()), nil
 	case value.Int:
-		return Lit(v.Value())
+		return Lit(v.Value()), nil
 	case value.Float:
-		return Lit(v.Value())
+		return Lit(v.Value()), nil
+	case value.Bool:
+		return Lit(v.Value()), nil
 	case value.Array:
+		elementType, err := resolveType(v.ElementType())
+		if err != nil {
+			return nil, err
+		}
+
 		elements := make([]Code, 0, len(v.Elements()))
-		for _, element := range v.Elements() {
-			elements = append(elements, resolveValue(element, context))
+		for i, element := range v.Elements() {
+			resolved, err := resolveValue(element, context)
+			if err != nil {
+				return nil, agnostic.InValue("elements["+strconv.Itoa(i)+"]", err)
+			}
+
+			elements = append(elements, resolved)
 		}
 
-		return Index().Add(resolveType(v.ElementType())).Values(elements...)
+		return Index().Add(elementType).Values(elements...), nil
 	case value.Map:
+		mapType, err := resolveType(types.NewMap(v.KeyType(), v.ValueType()))
+		if err != nil {
+			return nil, err
+		}
+
 		elements := make([]Code, 0, len(v.Elements()))
-		for _, element := range v.Elements() {
-			elements = append(elements, resolveValue(element.Key()).Op(":").Add(resolveValue(element.Value())))
+		for i, element := range v.Elements() {
+			key, err := resolveValue(element.Key(), context)
+			if err != nil {
+				return nil, agnostic.InValue("elements["+strconv.Itoa(i)+"].key", err)
+			}
+
+			elementValue, err := resolveValue(element.Value(), context)
+			if err != nil {
+				return nil, agnostic.InValue("elements["+strconv.Itoa(i)+"].value", err)
+			}
+
+			elements = append(elements, key.Op(":").Add(elementValue))
 		}
 
-		return Map(resolveType(v.KeyType())).Add(resolveType(v.ValueType())).Values(elements...)
+		return mapType.Values(elements...), nil
 	case value.OwnField:
-		return Id(context.receiverName).Op(".").Add(resolveValue(v.Field(), context))
+		field, err := resolveValue(v.Field(), context)
+		if err != nil {
+			return nil, agnostic.InValue("field", err)
+		}
+
+		return Id(context.receiverName).Op(".").Add(field), nil
 	case value.Id:
-		return Id(v.Name())
+		return Id(v.Name()), nil
 	case value.ModelField:
-		return Id(v.ModelName()).Op(".").Add(resolveValue(v.Field(), context))
+		field, err := resolveValue(v.Field(), context)
+		if err != nil {
+			return nil, agnostic.InValue("field", err)
+		}
+
+		return Id(v.ModelName()).Op(".").Add(field), nil
 	case value.ArrayElement:
-		return resolveValue(v.Array(), context).Index(resolveValue(v.Index(), context))
+		array, err := resolveValue(v.Array(), context)
+		if err != nil {
+			return nil, agnostic.InValue("array", err)
+		}
+
+		index, err := resolveValue(v.Index(), context)
+		if err != nil {
+			return nil, agnostic.InValue("index", err)
+		}
+
+		return array.Index(index), nil
 	case value.MapElement:
-		return resolveValue(v.Map(), context).Index(resolveValue(v.Key(), context))
+		mapValue, err := resolveValue(v.Map(), context)
+		if err != nil {
+			return nil, agnostic.InValue("map", err)
+		}
+
+		key, err := resolveValue(v.Key(), context)
+		if err != nil {
+			return nil, agnostic.InValue("key", err)
+		}
+
+		return mapValue.Index(key), nil
 	case value.Combined:
-		return resolveValue(v.Left(), context).Op(v.Operator().Value()).Add(resolveValue(v.Right(), context))
+		left, err := resolveOperand(v.Left(), v.Operator(), false, context)
+		if err != nil {
+			return nil, agnostic.InValue("left", err)
+		}
+
+		right, err := resolveOperand(v.Right(), v.Operator(), true, context)
+		if err != nil {
+			return nil, agnostic.InValue("right", err)
+		}
+
+		return left.Op(v.Operator().Value()).Add(right), nil
+	case value.Unary:
+		operand, err := resolveValue(v.Value(), context)
+		if err != nil {
+			return nil, agnostic.InValue("value", err)
+		}
+
+		if needsUnaryParentheses(v.Value()) {
+			operand = Parens(operand)
+		}
+
+		return Op(v.Operator().Value()).Add(operand), nil
 	case value.IntToString:
-		return Qual("strconv", "Itoa").Call(resolveValue(v.IntValue(), context))
+		intValue, err := resolveValue(v.IntValue(), context)
+		if err != nil {
+			return nil, agnostic.InValue("intValue", err)
+		}
+
+		return Qual("strconv", "Itoa").Call(intValue), nil
+	case value.OwnMethodCall:
+		arguments, err := resolveArguments(v.Arguments(), context)
+		if err != nil {
+			return nil, err
+		}
+
+		return Id(context.receiverName).Dot(v.Method()).Call(arguments...), nil
+	case value.MethodCall:
+		model, err := resolveValue(v.Model(), context)
+		if err != nil {
+			return nil, agnostic.InValue("model", err)
+		}
+
+		arguments, err := resolveArguments(v.Arguments(), context)
+		if err != nil {
+			return nil, err
+		}
+
+		return model.Dot(v.Method()).Call(arguments...), nil
+	case value.EnumValue:
+		return Id(v.EnumName() + "_" + v.ValueName()), nil
+	case value.Call:
+		arguments, err := resolveArguments(v.Arguments(), context)
+		if err != nil {
+			return nil, err
+		}
+
+		return Id(v.Function()).Call(arguments...), nil
 	default:
-		panic(errors.New(fmt.Sprintf("uknown type %T", v)))
+		return nil, fmt.Errorf("unsupported value %T in go", v)
 	}
 }
 
+// The precedence of each operator in Go. Operators with a higher precedence
+// bind more tightly
+var precedence = map[value.Operator]int{
+	value.Multiply:           5,
+	value.Divide:             5,
+	value.Modulo:             5,
+	value.Add:                4,
+	value.Subtract:           4,
+	value.Equal:              3,
+	value.NotEqual:           3,
+	value.GreatThan:          3,
+	value.GreatThanOrEqualTo: 3,
+	value.LessThan:           3,
+	value.LassThanOrEqualTo:  3,
+	value.And:                2,
+	value.Or:                 1,
+}
+
+// Resolves a value that one side of the operator is applied to. Combined values
+// are wrapped in parentheses whenever the precedence of the operators would
+// otherwise group them differently than the agnostic tree does. Every operator
+// is left associative so a right operand with the same precedence needs them
+// too
+func resolveOperand(operand value.Any, operator value.Operator, isRight bool, context *BodyImplementation) (*Statement, error) {
+	resolved, err := resolveValue(operand, context)
+	if err != nil {
+		return nil, err
+	}
+
+	if combined, ok := operand.(value.Combined); ok {
+		operandPrecedence := precedence[combined.Operator()]
+		if operandPrecedence < precedence[operator] || (isRight && operandPrecedence == precedence[operator]) {
+			return Parens(resolved), nil
+		}
+	}
+
+	return resolved, nil
+}
+
+// Whether the value has to be wrapped in parentheses to have a unary operator
+// applied to it. Negative literals are included so that negating them doesn't
+// produce a decrement operator
+func needsUnaryParentheses(operand value.Any) bool {
+	switch v := operand.(type) {
+	case value.Combined, value.Unary:
+		return true
+	case value.Int:
+		return v.Value() < 0
+	case value.Float:
+		return v.Value() < 0
+	default:
+		return false
+	}
+}
+
+func resolveArguments(arguments []value.Any, context *BodyImplementation) ([]Code, error) {
+	resolvedArguments := make([]Code, 0, len(arguments))
+	for i, argument := range arguments {
+		resolved, err := resolveValue(argument, context)
+		if err != nil {
+			return nil, agnostic.InValue("arguments["+strconv.Itoa(i)+"]", err)
+		}
+
+		resolvedArguments = append(resolvedArguments, resolved)
+	}
+
+	return resolvedArguments, nil
+}
+
+// Helper method to render a file containing the given code. The file is first
+// rendered in memory so that nothing is written if the code is invalid
+func render(packageName string, code []Code, out io.Writer) (n int64, err error) {
+	jenFile := NewFile(packageName)
+	jenFile.Add(lines(code...))
+
+	var buffer bytes.Buffer
+	err = jenFile.Render(&buffer)
+	if err != nil {
+		return 0, err
+	}
+
+	return buffer.WriteTo(out)
+}
+
 // Helper method to split a set of statements into lines of code
 func lines(statements ...Code) Code {
 	if len(statements) == 0 {
@@ -0,0 +1,76 @@
+package golang
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestMissingPackageName(t *testing.T) {
+	_, err := NewImplementation(map[string]string{})
+	require.EqualError(t, err, "no package name supplied")
+}
+
+func TestErrorsAreCollected(t *testing.T) {
+	implementation, err := NewImplementation(map[string]string{"package": "test"})
+	require.NoError(t, err)
+
+	implementation.Model("TestModel", agnostic.Field{Name: "value", Type: types.Base(-1)})
+	body := implementation.Method("TestModel", "SetValue")
+	body.Assign(value.NewOwnField(value.NewId("value")), value.NewArray(types.Base(-1)))
+	body.ForEachMapSorted(value.NewOwnField(value.NewId("flags")), types.BaseBool, "key", "value")
+	body.RemoveValue(value.NewOwnField(value.NewId("values")), value.NewArray(types.Base(-1)))
+	body.MapPut(value.NewOwnField(value.NewId("values")), value.NewCombined(value.NewInt(1), value.Add, value.NewArray(types.Base(-1))), value.NewInt(1))
+	functionBody := implementation.Function("GetValue", types.BaseInt)
+	functionBody.Return(value.NewOwnField(value.NewId("value")))
+
+	var contents strings.Builder
+	_, err = implementation.WriteTo(&contents)
+	require.Error(t, err)
+	require.Equal(t, strings.Join([]string{
+		"TestModel.value: unsupported base type -1 in go",
+		"TestModel.SetValue: Assign.assigned: unsupported base type -1 in go",
+		"TestModel.SetValue: map keys of type bool can't be sorted",
+		"TestModel.SetValue: RemoveValue.index: unsupported base type -1 in go",
+		"TestModel.SetValue: MapPut.key.right: unsupported base type -1 in go",
+		"GetValue: Return.value: method dependent value used outside of a method",
+	}, "\n"), err.Error())
+	require.Empty(t, contents.String())
+}
+
+func TestCallsAreChecked(t *testing.T) {
+	implementation, err := NewImplementation(map[string]string{"package": "test"})
+	require.NoError(t, err)
+
+	implementation.Model("TestModel", agnostic.Field{Name: "count", Type: types.BaseInt, Default: value.NewCall("makeCount")})
+	body := implementation.Method("TestModel", "Caller")
+	body.Call(value.NewOwnMethodCall("Later", value.NewString("wrong")))
+	body.Call(value.NewOwnMethodCall("Later", value.NewInt(1), value.NewInt(2)))
+	body.Call(value.NewCall("missing"))
+	body.Call(value.NewInt(1))
+	implementation.Method("TestModel", "Later", agnostic.Field{Name: "count", Type: types.BaseInt})
+	implementation.Function("Free", nil).Call(value.NewOwnMethodCall("Later", value.NewInt(1)))
+	typed := implementation.Method("TestModel", "Typed", agnostic.Field{Name: "name", Type: types.BaseString})
+	typed.Call(value.NewOwnMethodCall("Later", value.NewId("name")))
+	typed.Declare("copy", value.NewOwnField(value.NewId("count")))
+	typed.Call(value.NewOwnMethodCall("Later", value.NewId("copy")))
+	typed.Call(value.NewMethodCall(value.NewOwnField(value.NewId("count")), types.NewModel("TestModel"), "Later", value.NewId("copy")))
+
+	var contents strings.Builder
+	_, err = implementation.WriteTo(&contents)
+	require.Error(t, err)
+	require.Equal(t, strings.Join([]string{
+		"TestModel.Caller: value.Int can't be used as a statement",
+		"Free: Call.call: method dependent value used outside of a method",
+		"TestModel.count: call to undeclared method or function \"makeCount\"",
+		"TestModel.Caller: argument \"count\" of \"TestModel.Later\" can't be a value.String",
+		"TestModel.Caller: \"TestModel.Later\" takes 1 arguments but was called with 2",
+		"TestModel.Caller: call to undeclared method or function \"missing\"",
+		"TestModel.Typed: argument \"count\" of \"TestModel.Later\" can't be a value of type string",
+		"TestModel.Typed: \"TestModel.Later\" is called on a value of type int",
+	}, "\n"), err.Error())
+	require.Empty(t, contents.String())
+}
@@ -2,13 +2,17 @@ package golang
 
 import (
 	"errors"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/test"
 	. "github.com/dave/jennifer/jen"
+	"io"
 )
 
 type TestImplementation struct {
 	packageName string
 	code        []Code
+	errors      agnostic.ErrorList
 }
 
 func (g *TestImplementation) Receiver() string {
@@ -19,27 +23,42 @@ func (g *TestImplementation) Add(c ...Code) {
 	g.code = append(g.code, c...)
 }
 
-func (g *TestImplementation) Write(fileName string) {
-	jenFile := NewFile(g.packageName)
-	jenFile.Add(lines(g.code...))
-	err := jenFile.Save(fileName + ".go")
+func (g *TestImplementation) Write(fileName string) error {
+	return agnostic.WriteFile(fileName+".go", g)
+}
+
+func (g *TestImplementation) WriteTo(out io.Writer) (n int64, err error) {
+	if err = g.errors.Err(); err != nil {
+		return 0, err
+	}
+
+	return render(g.packageName, g.code, out)
+}
+
+// Resolves the value while recording any error against the given location
+func (g *TestImplementation) resolveValue(location string, any value.Any) *Statement {
+	resolved, err := resolveValue(any, nil)
 	if err != nil {
-		panic(err)
+		g.errors.Add(location, err)
+		return Null()
 	}
+
+	return resolved
 }
 
 func (g *TestImplementation) Test(testCase test.Case) {
 	for _, fact := range testCase.Facts {
+		testName := "Test" + testCase.Name + fact.Name
 		testBody := make([]Code, 0)
 
 		// Create an instance of the test model
-		createTestModel := Id("model").Op(":=").Id("TestModel").Block()
+		createTestModel := Id("model").Op(":=").Id("NewTestModel").Call()
 		testBody = append(testBody, createTestModel)
 
 		// Call the test model method
 		inputs := make([]Code, 0, len(fact.Inputs))
 		for _, input := range fact.Inputs {
-			inputs = append(inputs, resolveValue(input))
+			inputs = append(inputs, g.resolveValue(testName, input))
 		}
 		if fact.Output == nil {
 			callTestMethod := Id("model").Dot(testCase.Name).Call(inputs...)
@@ -51,16 +70,15 @@ func (g *TestImplementation) Test(testCase test.Case) {
 
 		// Assert that the output matches the expected value
 		if fact.Output != nil {
-			assertOutput := testifyRequire("Equal").Call(Id("t"), resolveValue(fact.Output), Id("output"))
+			assertOutput := testifyRequire("Equal").Call(Id("t"), g.resolveValue(testName, fact.Output), Id("output"))
 			testBody = append(testBody, assertOutput)
 		}
 
 		for _, sideEffect := range fact.SideEffects {
-			assertSideEffect := testifyRequire("Equal").Call(Id("t"), resolveValue(sideEffect.ExpectedValue), Id("model").Dot(sideEffect.FieldName))
+			assertSideEffect := testifyRequire("Equal").Call(Id("t"), g.resolveValue(testName, sideEffect.ExpectedValue), Id("model").Dot(sideEffect.FieldName))
 			testBody = append(testBody, assertSideEffect)
 		}
 
-		testName := "Test" + testCase.Name + fact.Name
 		g.Add(Func().Id(testName).Params(Id("t").Op("*").Qual("testing", "T")).Block(testBody...))
 	}
 }
@@ -69,14 +87,14 @@ func testifyRequire(assertion string) *Statement {
 	return Qual("github.com/stretchr/testify/require", assertion)
 }
 
-func NewTestImplementation(args map[string]string) test.Implementation {
+func NewTestImplementation(args map[string]string) (test.Implementation, error) {
 	packageName, ok := args["package"]
 	if !ok {
-		panic(errors.New("no package name supplied"))
+		return nil, errors.New("no package name supplied")
 	}
 
 	return &TestImplementation{
 		code:        make([]Code, 0),
 		packageName: packageName,
-	}
+	}, nil
 }
@@ -0,0 +1,287 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+	"io"
+	"math"
+	"strconv"
+)
+
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// A JSON Schema document or sub-schema
+type Schema map[string]interface{}
+
+// An implementation that outputs a single JSON Schema (draft 2020-12) document
+// that contains a definition for every model and enum. Each definition can be
+// referenced with "<id>#/$defs/<name>". JSON Schema has no way to represent
+// logic so method bodies are discarded
+type Implementation struct {
+	id          string
+	definitions Schema
+	enums       map[string][]string // The value names of every enum keyed by the enum's name
+	enumValues  []*enumValue
+	errors      agnostic.ErrorList
+}
+
+// An enum value used in a default. An enum may be declared after the model
+// that uses it so the index of the value is only found once the document is
+// written
+type enumValue struct {
+	location string
+	value    value.EnumValue
+	index    int
+}
+
+func (e *enumValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.index)
+}
+
+func NewImplementation(args map[string]string) (agnostic.Implementation, error) {
+	return &Implementation{
+		id:          args["id"],
+		definitions: make(Schema),
+		enums:       make(map[string][]string),
+	}, nil
+}
+
+func (i *Implementation) Write(fileName string) error {
+	return agnostic.WriteFile(fileName+".json", i)
+}
+
+func (i *Implementation) WriteTo(out io.Writer) (n int64, err error) {
+	checked := i.resolveEnumValues()
+	if err = checked.Err(); err != nil {
+		return 0, err
+	}
+
+	document := Schema{
+		"$schema": SchemaDialect,
+		"$defs":   i.definitions,
+	}
+	if i.id != "" {
+		document["$id"] = i.id
+	}
+
+	contents, err := json.MarshalIndent(document, "", "\t")
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := out.Write(append(contents, '\n'))
+	return int64(written), err
+}
+
+// Finds the index of every enum value used in a default. Returns the existing
+// errors along with an error for every value that isn't part of its enum
+func (i *Implementation) resolveEnumValues() agnostic.ErrorList {
+	checked := make(agnostic.ErrorList, len(i.errors))
+	copy(checked, i.errors)
+
+	for _, e := range i.enumValues {
+		e.index = -1
+		for index, valueName := range i.enums[e.value.EnumName()] {
+			if valueName == e.value.ValueName() {
+				e.index = index
+				break
+			}
+		}
+
+		if e.index == -1 {
+			checked.Add(e.location, fmt.Errorf("no value %q in enum %q", e.value.ValueName(), e.value.EnumName()))
+		}
+	}
+
+	return checked
+}
+
+func (i *Implementation) Model(name string, fields ...agnostic.Field) {
+	properties := make(Schema)
+	required := make([]string, 0, len(fields))
+	for _, field := range fields {
+		fieldSchema, err := resolveType(field.Type)
+		if err == nil && field.Default != nil {
+			fieldSchema["default"], err = i.resolveValue(name+"."+field.Name, field.Default)
+		}
+		if err != nil {
+			i.errors.Add(name+"."+field.Name, err)
+		}
+
+		properties[field.Name] = fieldSchema
+		required = append(required, field.Name)
+	}
+
+	i.definitions[name] = Schema{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// Enums are represented by their integer values
+func (i *Implementation) Enum(name string, values ...string) {
+	enumValues := make([]int, 0, len(values))
+	for v := range values {
+		enumValues = append(enumValues, v)
+	}
+
+	i.enums[name] = values
+	i.definitions[name] = Schema{
+		"type": "integer",
+		"enum": enumValues,
+	}
+}
+
+func (i *Implementation) Method(modelName, methodName string, parameters ...agnostic.Field) agnostic.BodyImplementation {
+	return agnostic.DiscardBody{}
+}
+
+func (i *Implementation) ReturnMethod(modelName, methodName string, returnType types.Any, parameters ...agnostic.Field) agnostic.BodyImplementation {
+	return agnostic.DiscardBody{}
+}
+
+func (i *Implementation) Function(name string, returnType types.Any, parameters ...agnostic.Field) agnostic.BodyImplementation {
+	return agnostic.DiscardBody{}
+}
+
+func resolveType(any types.Any) (Schema, error) {
+	switch t := any.(type) {
+	case types.Base:
+		return resolveBaseType(t)
+	case types.Model:
+		return Schema{"$ref": "#/$defs/" + t.ModelName()}, nil
+	case types.Array:
+		items, err := resolveType(t.Element())
+		if err != nil {
+			return nil, err
+		}
+
+		return Schema{
+			"type":  "array",
+			"items": items,
+		}, nil
+	case types.Map:
+		// JSON only supports string keys so other key types must be
+		// represented by their string form
+		propertyNames, err := resolveKeyType(t.Key())
+		if err != nil {
+			return nil, err
+		}
+
+		additionalProperties, err := resolveType(t.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		return Schema{
+			"type":                 "object",
+			"propertyNames":        propertyNames,
+			"additionalProperties": additionalProperties,
+		}, nil
+	case types.Pointer:
+		pointedTo, err := resolveType(t.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		return Schema{
+			"anyOf": []Schema{
+				pointedTo,
+				{"type": "null"},
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T in jsonschema", t)
+	}
+}
+
+func resolveKeyType(any types.Any) (Schema, error) {
+	switch any {
+	case types.BaseString:
+		return Schema{"type": "string"}, nil
+	case types.BaseInt, types.BaseInt32, types.BaseInt64:
+		return Schema{"type": "string", "pattern": "^-?[0-9]+$"}, nil
+	default:
+		return nil, errors.New("map keys must be an integer or string type in jsonschema")
+	}
+}
+
+func resolveBaseType(base types.Base) (Schema, error) {
+	switch base {
+	case types.BaseBool:
+		return Schema{"type": "boolean"}, nil
+	case types.BaseInt:
+		fallthrough
+	case types.BaseInt64:
+		return Schema{"type": "integer", "minimum": int64(math.MinInt64), "maximum": int64(math.MaxInt64)}, nil
+	case types.BaseInt32:
+		return Schema{"type": "integer", "minimum": math.MinInt32, "maximum": math.MaxInt32}, nil
+	case types.BaseFloat32:
+		return Schema{"type": "number", "minimum": -math.MaxFloat32, "maximum": math.MaxFloat32}, nil
+	case types.BaseFloat64:
+		return Schema{"type": "number"}, nil
+	case types.BaseString:
+		return Schema{"type": "string"}, nil
+	default:
+		return nil, errors.New("unsupported base type " + strconv.Itoa(int(base)) + " in jsonschema")
+	}
+}
+
+// Converts a literal value into the form that it will take in the JSON
+// document. Only literals and enum values can be represented. The location is
+// where the value is used
+func (i *Implementation) resolveValue(location string, any value.Any) (interface{}, error) {
+	switch v := any.(type) {
+	case value.Null:
+		return nil, nil
+	case value.String:
+		return v.Value(), nil
+	case value.Int:
+		return v.Value(), nil
+	case value.Float:
+		return v.Value(), nil
+	case value.Bool:
+		return v.Value(), nil
+	case value.Array:
+		elements := make([]interface{}, 0, len(v.Elements()))
+		for _, element := range v.Elements() {
+			resolved, err := i.resolveValue(location, element)
+			if err != nil {
+				return nil, err
+			}
+
+			elements = append(elements, resolved)
+		}
+
+		return elements, nil
+	case value.Map:
+		elements := make(map[string]interface{})
+		for _, element := range v.Elements() {
+			key, err := i.resolveValue(location, element.Key())
+			if err != nil {
+				return nil, err
+			}
+
+			elementValue, err := i.resolveValue(location, element.Value())
+			if err != nil {
+				return nil, err
+			}
+
+			elements[fmt.Sprint(key)] = elementValue
+		}
+
+		return elements, nil
+	case value.EnumValue:
+		resolved := &enumValue{location: location, value: v}
+		i.enumValues = append(i.enumValues, resolved)
+		return resolved, nil
+	default:
+		return nil, fmt.Errorf("unsupported value %T in jsonschema", v)
+	}
+}
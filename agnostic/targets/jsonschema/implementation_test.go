@@ -0,0 +1,93 @@
+package jsonschema
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const expectedSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://example.com/test.json",
+	"$defs": {
+		"TestModel": {
+			"type": "object",
+			"properties": {
+				"count": {"type": "integer", "minimum": -9223372036854775808, "maximum": 9223372036854775807, "default": 5},
+				"small": {"type": "integer", "minimum": -2147483648, "maximum": 2147483647},
+				"ratio": {"type": "number"},
+				"enabled": {"type": "boolean"},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"children": {
+					"type": "object",
+					"propertyNames": {"type": "string", "pattern": "^-?[0-9]+$"},
+					"additionalProperties": {"$ref": "#/$defs/TestModel"}
+				},
+				"maybe": {"anyOf": [{"type": "string"}, {"type": "null"}]},
+				"color": {"$ref": "#/$defs/Color", "default": 1}
+			},
+			"required": ["count", "small", "ratio", "enabled", "tags", "children", "maybe", "color"],
+			"additionalProperties": false
+		},
+		"Color": {"type": "integer", "enum": [0, 1, 2]}
+	}
+}`
+
+func TestWrite(t *testing.T) {
+	implementation, err := NewImplementation(map[string]string{"id": "https://example.com/test.json"})
+	require.NoError(t, err)
+
+	implementation.Model("TestModel",
+		agnostic.Field{Name: "count", Type: types.BaseInt, Default: value.NewInt(5)},
+		agnostic.Field{Name: "small", Type: types.BaseInt32},
+		agnostic.Field{Name: "ratio", Type: types.BaseFloat64},
+		agnostic.Field{Name: "enabled", Type: types.BaseBool},
+		agnostic.Field{Name: "tags", Type: types.NewArray(types.BaseString)},
+		agnostic.Field{Name: "children", Type: types.NewMap(types.BaseInt, types.NewModel("TestModel"))},
+		agnostic.Field{Name: "maybe", Type: types.NewPointer(types.BaseString)},
+		agnostic.Field{Name: "color", Type: types.NewModel("Color"), Default: value.NewEnumValue("Color", "Green")},
+	)
+	implementation.Enum("Color", "Red", "Green", "Blue")
+
+	var contents strings.Builder
+	_, err = implementation.WriteTo(&contents)
+	require.NoError(t, err)
+	require.JSONEq(t, expectedSchema, contents.String())
+}
+
+func TestUnsupportedMapKey(t *testing.T) {
+	implementation, err := NewImplementation(map[string]string{})
+	require.NoError(t, err)
+
+	implementation.Model("TestModel",
+		agnostic.Field{Name: "floatKey", Type: types.NewMap(types.BaseFloat64, types.BaseInt)},
+		agnostic.Field{Name: "boolKey", Type: types.NewMap(types.BaseBool, types.BaseInt)},
+	)
+
+	var contents strings.Builder
+	_, err = implementation.WriteTo(&contents)
+	require.Error(t, err)
+	require.Len(t, err, 2)
+	require.Contains(t, err.Error(), "TestModel.floatKey: map keys must be")
+	require.Contains(t, err.Error(), "TestModel.boolKey: map keys must be")
+	require.Empty(t, contents.String())
+}
+
+func TestUndeclaredEnumValue(t *testing.T) {
+	implementation, err := NewImplementation(map[string]string{})
+	require.NoError(t, err)
+
+	implementation.Model("TestModel",
+		agnostic.Field{Name: "color", Type: types.NewModel("Color"), Default: value.NewEnumValue("Color", "Purple")},
+	)
+	implementation.Enum("Color", "Red", "Green", "Blue")
+
+	var contents strings.Builder
+	_, err = implementation.WriteTo(&contents)
+	require.Error(t, err)
+	require.Equal(t, "TestModel.color: no value \"Purple\" in enum \"Color\"", err.Error())
+	require.Empty(t, contents.String())
+}
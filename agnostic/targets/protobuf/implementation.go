@@ -0,0 +1,192 @@
+package protobuf
+
+import (
+	"errors"
+	"fmt"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// An implementation that outputs a proto3 schema of the models and enums.
+// Protobuf has no way to represent logic so method bodies are discarded
+type Implementation struct {
+	packageName  string
+	declarations []string
+	errors       agnostic.ErrorList
+}
+
+func (i *Implementation) Add(declaration string) {
+	i.declarations = append(i.declarations, declaration)
+}
+
+func NewImplementation(args map[string]string) (agnostic.Implementation, error) {
+	return &Implementation{
+		packageName:  args["package"],
+		declarations: make([]string, 0),
+	}, nil
+}
+
+func (i *Implementation) Write(fileName string) error {
+	return agnostic.WriteFile(fileName+".proto", i)
+}
+
+func (i *Implementation) WriteTo(out io.Writer) (n int64, err error) {
+	if err = i.errors.Err(); err != nil {
+		return 0, err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("syntax = \"proto3\";\n")
+	if i.packageName != "" {
+		sb.WriteString("\npackage " + i.packageName + ";\n")
+	}
+
+	for _, declaration := range i.declarations {
+		sb.WriteString("\n" + declaration)
+	}
+
+	written, err := io.WriteString(out, sb.String())
+	return int64(written), err
+}
+
+// Field numbers are assigned in the order that the fields are declared. To
+// keep the numbers stable new fields must only be added to the end
+func (i *Implementation) Model(name string, fields ...agnostic.Field) {
+	var sb strings.Builder
+
+	sb.WriteString("message " + name + " {\n")
+	for number, field := range fields {
+		fieldType, err := resolveFieldType(field.Type)
+		if err != nil {
+			i.errors.Add(name+"."+field.Name, err)
+		}
+
+		sb.WriteString("\t" + fieldType + " " + field.Name + " = " + strconv.Itoa(number+1) + ";\n")
+	}
+	sb.WriteString("}\n")
+
+	i.Add(sb.String())
+}
+
+// Values are prefixed with the enum name because protobuf enum values share
+// the scope of the enum itself
+func (i *Implementation) Enum(name string, values ...string) {
+	var sb strings.Builder
+
+	sb.WriteString("enum " + name + " {\n")
+	for number, v := range values {
+		sb.WriteString("\t" + name + "_" + v + " = " + strconv.Itoa(number) + ";\n")
+	}
+	sb.WriteString("}\n")
+
+	i.Add(sb.String())
+}
+
+func (i *Implementation) Method(modelName, methodName string, parameters ...agnostic.Field) agnostic.BodyImplementation {
+	return agnostic.DiscardBody{}
+}
+
+func (i *Implementation) ReturnMethod(modelName, methodName string, returnType types.Any, parameters ...agnostic.Field) agnostic.BodyImplementation {
+	return agnostic.DiscardBody{}
+}
+
+func (i *Implementation) Function(name string, returnType types.Any, parameters ...agnostic.Field) agnostic.BodyImplementation {
+	return agnostic.DiscardBody{}
+}
+
+// Resolves the type of a message field along with any label that it needs
+func resolveFieldType(any types.Any) (string, error) {
+	switch t := any.(type) {
+	case types.Array:
+		element, err := resolveType(t.Element())
+		if err != nil {
+			return "", err
+		}
+
+		return "repeated " + element, nil
+	case types.Map:
+		key, err := resolveKeyType(t.Key())
+		if err != nil {
+			return "", err
+		}
+
+		mapValue, err := resolveType(t.Value())
+		if err != nil {
+			return "", err
+		}
+
+		return "map<" + key + ", " + mapValue + ">", nil
+	case types.Pointer:
+		if base, ok := t.Value().(types.Base); ok {
+			pointedTo, err := resolveBaseType(base)
+			if err != nil {
+				return "", err
+			}
+
+			return "optional " + pointedTo, nil
+		}
+
+		return resolveType(t)
+	default:
+		return resolveType(t)
+	}
+}
+
+// Resolves a type in a position that can't have a label (i.e. the element of
+// a repeated field or the value of a map)
+func resolveType(any types.Any) (string, error) {
+	switch t := any.(type) {
+	case types.Base:
+		return resolveBaseType(t)
+	case types.Model:
+		return t.ModelName(), nil
+	case types.Pointer:
+		// Message fields already track presence so a pointer to a model is
+		// the same as the model itself
+		if model, ok := t.Value().(types.Model); ok {
+			return model.ModelName(), nil
+		}
+
+		return "", errors.New("pointers to non-models are only supported as a field type in protobuf")
+	case types.Array:
+		return "", errors.New("arrays can't be nested inside of an array or map in protobuf")
+	case types.Map:
+		return "", errors.New("maps can't be nested inside of an array or map in protobuf")
+	default:
+		return "", fmt.Errorf("unsupported type %T in protobuf", t)
+	}
+}
+
+func resolveKeyType(any types.Any) (string, error) {
+	base, ok := any.(types.Base)
+	if !ok || base == types.BaseFloat32 || base == types.BaseFloat64 {
+		return "", errors.New("map keys must be an integer, bool, or string type in protobuf")
+	}
+
+	return resolveBaseType(base)
+}
+
+func resolveBaseType(base types.Base) (string, error) {
+	switch base {
+	case types.BaseBool:
+		return "bool", nil
+	case types.BaseInt:
+		fallthrough
+	case types.BaseInt64:
+		return "int64", nil
+	case types.BaseInt32:
+		return "int32", nil
+	case types.BaseFloat32:
+		return "float", nil
+	case types.BaseFloat64:
+		return "double", nil
+	case types.BaseString:
+		return "string", nil
+	default:
+		return "", errors.New("unsupported base type " + strconv.Itoa(int(base)) + " in protobuf")
+	}
+}
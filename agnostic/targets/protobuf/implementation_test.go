@@ -0,0 +1,81 @@
+package protobuf
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const expectedSchema = `syntax = "proto3";
+
+package test;
+
+message Child {
+	string name = 1;
+}
+
+message TestModel {
+	int64 count = 1;
+	int32 small = 2;
+	double ratio = 3;
+	float smallRatio = 4;
+	bool enabled = 5;
+	repeated string tags = 6;
+	map<int64, Child> children = 7;
+	Child child = 8;
+	optional int64 maybe = 9;
+	Color color = 10;
+}
+
+enum Color {
+	Color_Red = 0;
+	Color_Green = 1;
+}
+`
+
+func TestWrite(t *testing.T) {
+	implementation, err := NewImplementation(map[string]string{"package": "test"})
+	require.NoError(t, err)
+
+	implementation.Model("Child", agnostic.Field{Name: "name", Type: types.BaseString})
+	implementation.Model("TestModel",
+		agnostic.Field{Name: "count", Type: types.BaseInt},
+		agnostic.Field{Name: "small", Type: types.BaseInt32},
+		agnostic.Field{Name: "ratio", Type: types.BaseFloat64},
+		agnostic.Field{Name: "smallRatio", Type: types.BaseFloat32},
+		agnostic.Field{Name: "enabled", Type: types.BaseBool},
+		agnostic.Field{Name: "tags", Type: types.NewArray(types.BaseString)},
+		agnostic.Field{Name: "children", Type: types.NewMap(types.BaseInt, types.NewModel("Child"))},
+		agnostic.Field{Name: "child", Type: types.NewPointer(types.NewModel("Child"))},
+		agnostic.Field{Name: "maybe", Type: types.NewPointer(types.BaseInt)},
+		agnostic.Field{Name: "color", Type: types.NewModel("Color")},
+	)
+	implementation.Enum("Color", "Red", "Green")
+
+	var contents strings.Builder
+	_, err = implementation.WriteTo(&contents)
+	require.NoError(t, err)
+	require.Equal(t, expectedSchema, contents.String())
+}
+
+func TestUnsupportedTypes(t *testing.T) {
+	implementation, err := NewImplementation(map[string]string{})
+	require.NoError(t, err)
+
+	implementation.Model("TestModel",
+		agnostic.Field{Name: "nested", Type: types.NewArray(types.NewArray(types.BaseInt))},
+		agnostic.Field{Name: "floatKey", Type: types.NewMap(types.BaseFloat64, types.BaseInt)},
+		agnostic.Field{Name: "pointers", Type: types.NewArray(types.NewPointer(types.BaseInt))},
+	)
+
+	var contents strings.Builder
+	_, err = implementation.WriteTo(&contents)
+	require.Error(t, err)
+	require.Len(t, err, 3)
+	require.Contains(t, err.Error(), "TestModel.nested: arrays can't be nested")
+	require.Contains(t, err.Error(), "TestModel.floatKey: map keys must be")
+	require.Contains(t, err.Error(), "TestModel.pointers: pointers to non-models")
+	require.Empty(t, contents.String())
+}
@@ -3,14 +3,13 @@
 package typescript
 
 import (
-	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
 	"io"
-	"os"
 	"strconv"
 	"strings"
 )
@@ -21,6 +20,7 @@ type Code interface {
 
 type OrphanCode struct {
 	belongsTo string
+	location  string // Used to give context to errors
 	code      []Code
 }
 
@@ -28,9 +28,10 @@ func (o *OrphanCode) Add(code ...Code) {
 	o.code = append(o.code, code...)
 }
 
-func NewOrphanCode(belongsTo string) *OrphanCode {
+func NewOrphanCode(belongsTo, location string) *OrphanCode {
 	return &OrphanCode{
 		belongsTo: belongsTo,
+		location:  location,
 		code:      make([]Code, 0),
 	}
 }
@@ -46,6 +47,10 @@ type Implementation struct {
 	code        []Code
 	modelBodies map[string]*BodyImplementation
 	orphans     []*OrphanCode
+	models      map[string][]agnostic.Field // Fields of every model that has been declared
+	enums       map[string]bool             // Names of every enum that has been declared
+	calls       agnostic.CallChecker
+	errors      agnostic.ErrorList
 }
 
 func (i *Implementation) Add(code ...Code) {
@@ -60,20 +65,39 @@ func (i *Implementation) AddOrphan(orphan *OrphanCode) {
 	i.orphans = append(i.orphans, orphan)
 }
 
-func NewImplementation(args map[string]string) agnostic.Implementation {
+func NewImplementation(args map[string]string) (agnostic.Implementation, error) {
 	return &Implementation{
 		code:        make([]Code, 0),
 		modelBodies: make(map[string]*BodyImplementation),
 		orphans:     make([]*OrphanCode, 0),
+		models:      make(map[string][]agnostic.Field),
+		enums:       make(map[string]bool),
+	}, nil
+}
+
+// Resolves the type while recording any error against the given location
+func (i *Implementation) resolveType(location string, any types.Any) string {
+	resolved, err := resolveType(any)
+	if err != nil {
+		i.errors.Add(location, err)
 	}
+
+	return resolved
 }
 
 type BodyImplementation struct {
-	code []Code
+	modelName  string          // Empty if the body has no model to refer to
+	location   string          // Used to give context to errors
+	scope      *agnostic.Scope // The variables that can be used by the next statement
+	calls      *agnostic.CallChecker
+	errors     *agnostic.ErrorList
+	code       []Code
+	endsInJump bool // Whether the last statement is a return, break, or continue
 }
 
 func (b *BodyImplementation) Add(code ...Code) {
 	b.code = append(b.code, code...)
+	b.endsInJump = false
 }
 
 func (b *BodyImplementation) Write(out io.Writer, indentLevel int) error {
@@ -91,46 +115,132 @@ func NewBodyImplementation() *BodyImplementation {
 	return &BodyImplementation{code: make([]Code, 0)}
 }
 
-func (i *Implementation) Write(fileName string) {
+// Creates a body for a method of the given model that takes the parameters.
+// Calls will be recorded in the given checker and errors in the given list
+func NewMethodBodyImplementation(modelName, location string, parameters []agnostic.Field, calls *agnostic.CallChecker, errors *agnostic.ErrorList) *BodyImplementation {
+	return &BodyImplementation{
+		modelName: modelName,
+		location:  location,
+		scope:     agnostic.NewScope(parameters),
+		calls:     calls,
+		errors:    errors,
+		code:      make([]Code, 0),
+	}
+}
+
+// Creates a body for a function that takes the parameters. Calls will be
+// recorded in the given checker and errors in the given list
+func NewFunctionBodyImplementation(location string, parameters []agnostic.Field, calls *agnostic.CallChecker, errors *agnostic.ErrorList) *BodyImplementation {
+	return &BodyImplementation{
+		location: location,
+		scope:    agnostic.NewScope(parameters),
+		calls:    calls,
+		errors:   errors,
+		code:     make([]Code, 0),
+	}
+}
+
+// Creates a body that runs inside of this one. For example, the body of an if
+// statement
+func (b *BodyImplementation) child() *BodyImplementation {
+	return &BodyImplementation{
+		modelName: b.modelName,
+		location:  b.location,
+		scope:     b.scope,
+		calls:     b.calls,
+		errors:    b.errors,
+		code:      make([]Code, 0),
+	}
+}
+
+// Resolves the value while recording any error against this body's location.
+// The path names the value within the statement that uses it
+func (b *BodyImplementation) resolveValue(path string, any value.Any) string {
+	if b.modelName == "" && any.IsMethodDependent() {
+		b.errors.Add(b.location, agnostic.InValue(path, errors.New("method dependent value used outside of a method")))
+		return ""
+	}
+
+	b.calls.Calls(b.location, b.modelName, b.scope, any)
+
+	resolved, err := resolveValue(any)
+	if err != nil {
+		b.errors.Add(b.location, agnostic.InValue(path, err))
+	}
+
+	return resolved
+}
+
+func (i *Implementation) Write(fileName string) error {
+	return agnostic.WriteFile(fileName+".ts", i)
+}
+
+func (i *Implementation) WriteTo(out io.Writer) (n int64, err error) {
 	for _, orphan := range i.orphans {
 		body, ok := i.modelBodies[orphan.belongsTo]
 		if !ok {
-			panic(errors.New("no model with name \"" + orphan.belongsTo + "\" found for method"))
+			i.errors.Add(orphan.location, errors.New("no model with name \""+orphan.belongsTo+"\" found for method"))
+			continue
 		}
 
 		body.Add(orphan.code...)
 	}
 
-	file, err := os.Create(fileName + ".ts")
-	if err != nil {
-		panic(err)
-	}
+	// Every orphan now belongs to its model so they must not be added again
+	// on subsequent writes
+	i.orphans = i.orphans[:0]
 
-	writer := bufio.NewWriter(file)
+	if err = i.calls.Check(i.errors).Err(); err != nil {
+		return 0, err
+	}
 
+	var buffer bytes.Buffer
 	for _, c := range i.code {
-		err = c.Write(writer, 0)
+		err = c.Write(&buffer, 0)
 		if err != nil {
-			panic(err)
+			return 0, err
 		}
 	}
 
-	err = writer.Flush()
-	if err != nil {
-		panic(err)
-	}
-
-	err = file.Close()
-	if err != nil {
-		panic(err)
-	}
+	return buffer.WriteTo(out)
 }
 
 func (i *Implementation) Model(name string, fields ...agnostic.Field) {
 	body := NewBodyImplementation()
+	constructorBody := NewBodyImplementation()
 	for _, field := range fields {
-		body.Add(Line(field.Name + ": " + resolveType(field.Type) + ";"))
+		fieldType, err := resolveType(field.Type)
+		initialValue := ""
+		if err == nil {
+			if field.Default != nil {
+				i.calls.Calls(name+"."+field.Name, "", nil, field.Default)
+			}
+			initialValue, err = resolveInitialValue(field)
+		}
+		if err != nil {
+			i.errors.Add(name+"."+field.Name, err)
+		}
+
+		body.Add(Line(field.Name + ": " + fieldType + ";"))
+
+		if model, ok := field.Type.(types.Model); ok && field.Default == nil {
+			constructorBody.Add(&modelFieldInitializer{
+				implementation: i,
+				modelName:      name,
+				fieldName:      field.Name,
+				fieldModel:     model.ModelName(),
+			})
+			continue
+		}
+
+		constructorBody.Add(Line("this." + field.Name + " = " + initialValue + ";"))
 	}
+	i.models[name] = fields
+	i.calls.Model(name, fields)
+
+	body.Add(Line("constructor() {"))
+	body.Add(constructorBody)
+	body.Add(Line("}"))
 
 	i.Add(Line("export class " + name + "{"))
 	i.Add(body)
@@ -139,33 +249,74 @@ func (i *Implementation) Model(name string, fields ...agnostic.Field) {
 	i.RegisterModel(name, body)
 }
 
+// Sets a field whose type is another model in a constructor. An enum starts as
+// its first value and a model starts as a new instance. A model that would end
+// up constructing the model that the field belongs to again is left undefined
+// so that the constructor doesn't recurse forever. Enums and models may be
+// declared after the field so this is decided when the code is written
+type modelFieldInitializer struct {
+	implementation *Implementation
+	modelName      string
+	fieldName      string
+	fieldModel     string
+}
+
+func (m *modelFieldInitializer) Write(out io.Writer, indentLevel int) error {
+	var initialValue string
+	switch {
+	case m.implementation.enums[m.fieldModel]:
+		initialValue = "0"
+	case m.fieldModel == m.modelName || m.implementation.constructs(m.fieldModel, m.modelName, make(map[string]bool)):
+		return nil
+	default:
+		initialValue = "new " + m.fieldModel + "()"
+	}
+
+	return Line("this."+m.fieldName+" = "+initialValue+";").Write(out, indentLevel)
+}
+
+// Whether constructing the model will construct the target model through its
+// fields or the fields of the models that it constructs
+func (i *Implementation) constructs(modelName, target string, visited map[string]bool) bool {
+	visited[modelName] = true
+	for _, field := range i.models[modelName] {
+		model, ok := field.Type.(types.Model)
+		if !ok || field.Default != nil || i.enums[model.ModelName()] {
+			continue
+		}
+
+		if model.ModelName() == target {
+			return true
+		}
+		if !visited[model.ModelName()] && i.constructs(model.ModelName(), target, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (i *Implementation) Enum(name string, values ...string) {
+	i.enums[name] = true
 	enumBody := NewBodyImplementation()
 	for _, v := range values {
-		enumBody.Add(Line(v))
+		enumBody.Add(Line(v + ","))
 	}
 
-	i.Add(Line("enum " + name + "{"))
+	i.Add(Line("export enum " + name + "{"))
 	i.Add(enumBody)
 	i.Add(Line("}"))
 }
 
 func (i *Implementation) Method(modelName, methodName string, parameters ...agnostic.Field) agnostic.BodyImplementation {
-	orphan := NewOrphanCode(modelName)
+	location := modelName + "." + methodName
+	orphan := NewOrphanCode(modelName, location)
 	i.AddOrphan(orphan)
 
-	var parametersString strings.Builder
-	for i, parameter := range parameters {
-		parametersString.WriteString(parameter.Name + ": " + resolveType(parameter.Type))
-
-		if i+1 != len(parameters) {
-			parametersString.WriteString(", ")
-		}
-	}
-
-	methodBody := NewBodyImplementation()
+	methodBody := NewMethodBodyImplementation(modelName, location, parameters, &i.calls, &i.errors)
+	i.calls.Method(modelName, methodName, parameters)
 
-	orphan.Add(Line("public " + methodName + "(" + parametersString.String() + ") {"))
+	orphan.Add(Line("public " + methodName + "(" + i.resolveParameters(location, parameters) + ") {"))
 	orphan.Add(methodBody)
 	orphan.Add(Line("}"))
 
@@ -173,82 +324,248 @@ func (i *Implementation) Method(modelName, methodName string, parameters ...agno
 }
 
 func (i *Implementation) ReturnMethod(modelName, methodName string, returnType types.Any, parameters ...agnostic.Field) agnostic.BodyImplementation {
-	orphan := NewOrphanCode(modelName)
+	location := modelName + "." + methodName
+	orphan := NewOrphanCode(modelName, location)
 	i.AddOrphan(orphan)
 
+	methodBody := NewMethodBodyImplementation(modelName, location, parameters, &i.calls, &i.errors)
+	i.calls.Method(modelName, methodName, parameters)
+
+	orphan.Add(Line("public " + methodName + "(" + i.resolveParameters(location, parameters) + "): " + i.resolveType(location, returnType) + "{"))
+	orphan.Add(methodBody)
+	orphan.Add(Line("}"))
+
+	return methodBody
+}
+
+func (i *Implementation) Function(name string, returnType types.Any, parameters ...agnostic.Field) agnostic.BodyImplementation {
+	functionBody := NewFunctionBodyImplementation(name, parameters, &i.calls, &i.errors)
+	i.calls.Function(name, parameters)
+
+	signature := "export function " + name + "(" + i.resolveParameters(name, parameters) + ")"
+	if returnType != nil {
+		signature += ": " + i.resolveType(name, returnType)
+	}
+
+	i.Add(Line(signature + " {"))
+	i.Add(functionBody)
+	i.Add(Line("}"))
+
+	return functionBody
+}
+
+func (i *Implementation) resolveParameters(location string, parameters []agnostic.Field) string {
 	var parametersString strings.Builder
-	for i, parameter := range parameters {
-		parametersString.WriteString(parameter.Name + ": " + resolveType(parameter.Type))
+	for index, parameter := range parameters {
+		parametersString.WriteString(parameter.Name + ": " + i.resolveType(location, parameter.Type))
 
-		if i+1 != len(parameters) {
+		if index+1 != len(parameters) {
 			parametersString.WriteString(", ")
 		}
 	}
 
-	methodBody := NewBodyImplementation()
-
-	orphan.Add(Line("public " + methodName + "(" + parametersString.String() + "): " + resolveType(returnType) + "{"))
-	orphan.Add(methodBody)
-	orphan.Add(Line("}"))
-
-	return methodBody
+	return parametersString.String()
 }
 
 func (b *BodyImplementation) Assign(assignee, assigned value.Any) {
-	b.Add(Line(resolveValue(assignee) + " = " + resolveValue(assigned) + ";"))
+	b.Add(Line(b.resolveValue("Assign.assignee", assignee) + " = " + b.resolveValue("Assign.assigned", assigned) + ";"))
 }
 
 func (b *BodyImplementation) Declare(name string, value value.Any) {
-	b.Add(Line("let " + name + " = " + resolveValue(value) + ";"))
+	b.Add(Line("let " + name + " = " + b.resolveValue("Declare.value", value) + ";"))
+	b.scope = b.scope.DeclareValue(name, value)
 }
 
 func (b *BodyImplementation) AppendValue(array, value value.Any) {
-	b.Add(Line(resolveValue(array) + ".push(" + resolveValue(value) + ");"))
+	b.Add(Line(b.resolveValue("AppendValue.array", array) + ".push(" + b.resolveValue("AppendValue.value", value) + ");"))
 }
 
 func (b *BodyImplementation) AppendArray(array, valueArray value.Any) {
-	b.Add(Line(resolveValue(array) + ".push(..." + resolveValue(valueArray) + ");"))
+	b.Add(Line(b.resolveValue("AppendArray.array", array) + ".push(..." + b.resolveValue("AppendArray.valueArray", valueArray) + ");"))
 }
 
 func (b *BodyImplementation) RemoveValue(array, index value.Any) {
-	b.Add(Line(resolveValue(array) + ".splice(" + resolveValue(index) + ", 1);"))
+	b.Add(Line(b.resolveValue("RemoveValue.array", array) + ".splice(" + b.resolveValue("RemoveValue.index", index) + ", 1);"))
 }
 
 func (b *BodyImplementation) MapPut(mapValue, key, value value.Any) {
-	b.Add(Line(resolveValue(mapValue) + ".set(" + resolveValue(key) + ", " + resolveValue(value) + ");"))
+	b.Add(Line(b.resolveValue("MapPut.mapValue", mapValue) + ".set(" + b.resolveValue("MapPut.key", key) + ", " + b.resolveValue("MapPut.value", value) + ");"))
 }
 
 func (b *BodyImplementation) MapDelete(mapValue, key value.Any) {
-	b.Add(Line(resolveValue(mapValue) + ".delete(" + resolveValue(key) + ");"))
+	b.Add(Line(b.resolveValue("MapDelete.mapValue", mapValue) + ".delete(" + b.resolveValue("MapDelete.key", key) + ");"))
 }
 
 func (b *BodyImplementation) ForEach(array value.Any, indexName, valueName string) agnostic.BodyImplementation {
-	forEachBody := NewBodyImplementation()
+	forEachBody := b.child()
 
-	forEachParams := ""
+	// A for...of loop is used rather than Array.forEach so that break and
+	// continue apply to the loop
+	var loopHeader string
 	if indexName == "" {
-		if valueName != "" {
-			forEachParams = valueName
+		if valueName == "" {
+			loopHeader = "const _ of " + b.resolveValue("ForEach.array", array)
+		} else {
+			loopHeader = "const " + valueName + " of " + b.resolveValue("ForEach.array", array)
 		}
 	} else {
 		if valueName == "" {
-			forEachParams = "_, " + indexName
+			loopHeader = "const " + indexName + " of " + b.resolveValue("ForEach.array", array) + ".keys()"
 		} else {
-			forEachParams = valueName + ", " + indexName
+			loopHeader = "const [" + indexName + ", " + valueName + "] of " + b.resolveValue("ForEach.array", array) + ".entries()"
 		}
 	}
 
-	b.Add(Line(resolveValue(array) + ".forEach((" + forEachParams + ") => {"))
+	forEachBody.scope = forEachBody.scope.DeclareLoopVariables(indexName, types.BaseInt, valueName, value.NewArrayElement(array, value.NewId(indexName)))
+
+	b.Add(Line("for (" + loopHeader + ") {"))
 	b.Add(forEachBody)
-	b.Add(Line("});"))
+	b.Add(Line("}"))
 
 	return forEachBody
 }
 
+func (b *BodyImplementation) ForEachMap(mapValue value.Any, keyName, valueName string) agnostic.BodyImplementation {
+	forEachBody := b.child()
+
+	var loopHeader string
+	if keyName == "" {
+		if valueName == "" {
+			loopHeader = "const _ of " + b.resolveValue("ForEachMap.mapValue", mapValue)
+		} else {
+			loopHeader = "const " + valueName + " of " + b.resolveValue("ForEachMap.mapValue", mapValue) + ".values()"
+		}
+	} else {
+		if valueName == "" {
+			loopHeader = "const " + keyName + " of " + b.resolveValue("ForEachMap.mapValue", mapValue) + ".keys()"
+		} else {
+			loopHeader = "const [" + keyName + ", " + valueName + "] of " + b.resolveValue("ForEachMap.mapValue", mapValue)
+		}
+	}
+
+	forEachBody.scope = forEachBody.scope.DeclareLoopVariables(keyName, nil, valueName, value.NewMapElement(mapValue, value.NewId(keyName)))
+
+	b.Add(Line("for (" + loopHeader + ") {"))
+	b.Add(forEachBody)
+	b.Add(Line("}"))
+
+	return forEachBody
+}
+
+// The keys are sorted with an explicit comparison because Array.sort compares
+// numbers by their string form by default
+func (b *BodyImplementation) ForEachMapSorted(mapValue value.Any, keyType types.Base, keyName, valueName string) agnostic.BodyImplementation {
+	forEachBody := b.child()
+
+	_, err := resolveBaseType(keyType)
+	if err == nil && keyType == types.BaseBool {
+		err = errors.New("map keys of type bool can't be sorted")
+	}
+	if err != nil {
+		b.errors.Add(b.location, err)
+		return forEachBody
+	}
+
+	// A generated name is used when there's no key name so that it can't hide
+	// any of the variables that the body refers to
+	key := keyName
+	if key == "" {
+		key = agnostic.ReservedPrefix + "Key"
+	}
+	resolvedMap := b.resolveValue("ForEachMapSorted.mapValue", mapValue)
+	forEachBody.scope = forEachBody.scope.DeclareLoopVariables(keyName, keyType, valueName, value.NewMapElement(mapValue, value.NewId(key)))
+
+	if valueName != "" {
+		forEachBody.Add(Line("const " + valueName + " = " + resolvedMap + ".get(" + key + ");"))
+	}
+
+	b.Add(Line("for (const " + key + " of Array.from(" + resolvedMap + ".keys()).sort((a, b) => a < b ? -1 : a > b ? 1 : 0)) {"))
+	b.Add(forEachBody)
+	b.Add(Line("}"))
+
+	return forEachBody
+}
+
+func (b *BodyImplementation) ForRange(indexName string, start, end value.Any) agnostic.BodyImplementation {
+	forRangeBody := b.child()
+	forRangeBody.scope = forRangeBody.scope.Declare(indexName, types.BaseInt)
+
+	b.Add(Line("for (let " + indexName + " = " + b.resolveValue("ForRange.start", start) + "; " + indexName + " < " + b.resolveValue("ForRange.end", end) + "; " + indexName + "++) {"))
+	b.Add(forRangeBody)
+	b.Add(Line("}"))
+
+	return forRangeBody
+}
+
+func (b *BodyImplementation) While(value value.Any) agnostic.BodyImplementation {
+	whileBody := b.child()
+
+	b.Add(Line("while (" + b.resolveValue("While.value", value) + ") {"))
+	b.Add(whileBody)
+	b.Add(Line("}"))
+
+	return whileBody
+}
+
+func (b *BodyImplementation) Break() {
+	b.Add(Line("break;"))
+	b.endsInJump = true
+}
+
+func (b *BodyImplementation) Continue() {
+	b.Add(Line("continue;"))
+	b.endsInJump = true
+}
+
+// Ends a switch clause with a break so that it doesn't fall through into the
+// next clause like it would by default in TypeScript. The break is left out if
+// the clause already ends with a jump since it would be unreachable
+type clauseEnd struct {
+	clause *BodyImplementation
+}
+
+func (c clauseEnd) Write(out io.Writer, indentLevel int) error {
+	if c.clause.endsInJump {
+		return nil
+	}
+
+	return Line("break;").Write(out, indentLevel)
+}
+
+// Each case is given its own block that ends in a clauseEnd
+func (b *BodyImplementation) Switch(value value.Any, cases ...value.Any) (caseBodies []agnostic.BodyImplementation, defaultBody agnostic.BodyImplementation) {
+	switchBody := b.child()
+
+	addClause := func(label string) *BodyImplementation {
+		clauseBody := switchBody.child()
+		breakBody := switchBody.child()
+		breakBody.Add(clauseEnd{clause: clauseBody})
+
+		switchBody.Add(Line(label + ": {"))
+		switchBody.Add(clauseBody)
+		switchBody.Add(breakBody)
+		switchBody.Add(Line("}"))
+
+		return clauseBody
+	}
+
+	caseBodies = make([]agnostic.BodyImplementation, 0, len(cases))
+	for i, c := range cases {
+		caseBodies = append(caseBodies, addClause("case "+b.resolveValue("Switch.cases["+strconv.Itoa(i)+"]", c)))
+	}
+	defaultBody = addClause("default")
+
+	b.Add(Line("switch (" + b.resolveValue("Switch.value", value) + ") {"))
+	b.Add(switchBody)
+	b.Add(Line("}"))
+
+	return caseBodies, defaultBody
+}
+
 func (b *BodyImplementation) If(value value.Any) agnostic.BodyImplementation {
-	ifBody := NewBodyImplementation()
+	ifBody := b.child()
 
-	b.Add(Line("if (" + resolveValue(value) + ") {"))
+	b.Add(Line("if (" + b.resolveValue("If.value", value) + ") {"))
 	b.Add(ifBody)
 	b.Add(Line("}"))
 
@@ -256,10 +573,10 @@ func (b *BodyImplementation) If(value value.Any) agnostic.BodyImplementation {
 }
 
 func (b *BodyImplementation) IfElse(value value.Any) (trueBody, falseBody agnostic.BodyImplementation) {
-	ifBody := NewBodyImplementation()
-	elseBody := NewBodyImplementation()
+	ifBody := b.child()
+	elseBody := b.child()
 
-	b.Add(Line("if (" + resolveValue(value) + ") {"))
+	b.Add(Line("if (" + b.resolveValue("IfElse.value", value) + ") {"))
 	b.Add(ifBody)
 	b.Add(Line("} else {"))
 	b.Add(elseBody)
@@ -268,31 +585,54 @@ func (b *BodyImplementation) IfElse(value value.Any) (trueBody, falseBody agnost
 	return ifBody, elseBody
 }
 
+func (b *BodyImplementation) Call(call value.Any) {
+	switch call.(type) {
+	case value.Call, value.OwnMethodCall, value.MethodCall:
+		b.Add(Line(b.resolveValue("Call.call", call) + ";"))
+	default:
+		b.errors.Add(b.location, fmt.Errorf("%T can't be used as a statement", call))
+	}
+}
+
 func (b *BodyImplementation) Return(value value.Any) {
-	b.Add(Line("return " + resolveValue(value) + ";"))
+	b.Add(Line("return " + b.resolveValue("Return.value", value) + ";"))
+	b.endsInJump = true
 }
 
-func resolveType(any types.Any) string {
+func resolveType(any types.Any) (string, error) {
 	switch t := any.(type) {
 	case types.Base:
 		return resolveBaseType(t)
 	case types.Model:
-		return t.ModelName()
+		return t.ModelName(), nil
 	case types.Array:
-		return resolveType(t.Element()) + "[]"
+		element, err := resolveType(t.Element())
+		if err != nil {
+			return "", err
+		}
+
+		return element + "[]", nil
 	case types.Map:
-		return "Map<" + resolveType(t.Key()) + ", " + resolveType(t.Value()) + ">"
-	case types.Pointer:
-		panic(errors.New("pointers are not supported yet"))
+		key, err := resolveType(t.Key())
+		if err != nil {
+			return "", err
+		}
+
+		mapValue, err := resolveType(t.Value())
+		if err != nil {
+			return "", err
+		}
+
+		return "Map<" + key + ", " + mapValue + ">", nil
 	default:
-		panic(errors.New(fmt.Sprintf("unkown type %T", t)))
+		return "", fmt.Errorf("unsupported type %T in typescript", t)
 	}
 }
 
-func resolveBaseType(base types.Base) string {
+func resolveBaseType(base types.Base) (string, error) {
 	switch base {
 	case types.BaseBool:
-		return "boolean"
+		return "boolean", nil
 	case types.BaseInt:
 		fallthrough
 	case types.BaseInt32:
@@ -302,30 +642,75 @@ func resolveBaseType(base types.Base) string {
 	case types.BaseFloat32:
 		fallthrough
 	case types.BaseFloat64:
-		return "number"
+		return "number", nil
 	case types.BaseString:
-		return "string"
+		return "string", nil
 	default:
-		panic(errors.New("unknown base type " + string(base)))
+		return "", errors.New("unsupported base type " + strconv.Itoa(int(base)) + " in typescript")
 	}
 }
 
-func resolveValue(any value.Any) string {
+// Resolves the value that a field starts with in a newly created model
+func resolveInitialValue(field agnostic.Field) (string, error) {
+	if field.Default != nil {
+		if field.Default.IsMethodDependent() {
+			return "", agnostic.InValue("Default", errors.New("method dependent value used as a default"))
+		}
+
+		resolved, err := resolveValue(field.Default)
+		return resolved, agnostic.InValue("Default", err)
+	}
+
+	switch t := field.Type.(type) {
+	case types.Base:
+		switch t {
+		case types.BaseBool:
+			return "false", nil
+		case types.BaseString:
+			return "\"\"", nil
+		default:
+			return "0", nil
+		}
+	case types.Array:
+		return "[]", nil
+	case types.Map:
+		mapType, err := resolveType(t)
+		if err != nil {
+			return "", err
+		}
+
+		return "new " + mapType + "()", nil
+	case types.Model:
+		// Decided by modelFieldInitializer once every model and enum is known
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported type %T in typescript", t)
+	}
+}
+
+func resolveValue(any value.Any) (string, error) {
 	switch v := any.(type) {
 	case value.Null:
-		return "null"
+		return "null", nil
 	case value.String:
-		return "\"" + v.Value() + "\""
+		return "\"" + v.Value() + "\"", nil
 	case value.Int:
-		return strconv.Itoa(v.Value())
+		return strconv.Itoa(v.Value()), nil
 	case value.Float:
-		return strconv.FormatFloat(v.Value(), 'f', -1, 64)
+		return strconv.FormatFloat(v.Value(), 'f', -1, 64), nil
+	case value.Bool:
+		return strconv.FormatBool(v.Value()), nil
 	case value.Array:
 		var sb strings.Builder
 
 		sb.WriteString("[")
 		for i, element := range v.Elements() {
-			sb.WriteString(resolveValue(element))
+			resolved, err := resolveValue(element)
+			if err != nil {
+				return "", agnostic.InValue("elements["+strconv.Itoa(i)+"]", err)
+			}
+
+			sb.WriteString(resolved)
 
 			if i+1 != len(v.Elements()) {
 				sb.WriteString(", ")
@@ -333,18 +718,33 @@ func resolveValue(any value.Any) string {
 		}
 		sb.WriteString("]")
 
-		return sb.String()
+		return sb.String(), nil
 	case value.Map:
+		mapType, err := resolveType(types.NewMap(v.KeyType(), v.ValueType()))
+		if err != nil {
+			return "", err
+		}
+
 		var sb strings.Builder
 
 		sb.WriteString("new ")
-		sb.WriteString(resolveType(types.NewMap(v.KeyType(), v.ValueType())))
+		sb.WriteString(mapType)
 		sb.WriteString("([")
 		for i, element := range v.Elements() {
+			key, err := resolveValue(element.Key())
+			if err != nil {
+				return "", agnostic.InValue("elements["+strconv.Itoa(i)+"].key", err)
+			}
+
+			elementValue, err := resolveValue(element.Value())
+			if err != nil {
+				return "", agnostic.InValue("elements["+strconv.Itoa(i)+"].value", err)
+			}
+
 			sb.WriteString("[")
-			sb.WriteString(resolveValue(element.Key()))
+			sb.WriteString(key)
 			sb.WriteString(", ")
-			sb.WriteString(resolveValue(element.Value()))
+			sb.WriteString(elementValue)
 			sb.WriteString("]")
 
 			if i-1 != len(v.Elements()) {
@@ -353,22 +753,180 @@ func resolveValue(any value.Any) string {
 		}
 		sb.WriteString("])")
 
-		return sb.String()
+		return sb.String(), nil
 	case value.OwnField:
-		return "this." + resolveValue(v.Field())
+		field, err := resolveValue(v.Field())
+		if err != nil {
+			return "", agnostic.InValue("field", err)
+		}
+
+		return "this." + field, nil
 	case value.Id:
-		return v.Name()
+		return v.Name(), nil
 	case value.ModelField:
-		return v.ModelName() + "." + resolveValue(v.Field())
+		field, err := resolveValue(v.Field())
+		if err != nil {
+			return "", agnostic.InValue("field", err)
+		}
+
+		return v.ModelName() + "." + field, nil
 	case value.ArrayElement:
-		return resolveValue(v.Array()) + "[" + resolveValue(v.Index()) + "]"
+		array, err := resolveValue(v.Array())
+		if err != nil {
+			return "", agnostic.InValue("array", err)
+		}
+
+		index, err := resolveValue(v.Index())
+		if err != nil {
+			return "", agnostic.InValue("index", err)
+		}
+
+		return array + "[" + index + "]", nil
 	case value.MapElement:
-		return resolveValue(v.Map()) + ".get(" + resolveValue(v.Key()) + ")"
+		mapValue, err := resolveValue(v.Map())
+		if err != nil {
+			return "", agnostic.InValue("map", err)
+		}
+
+		key, err := resolveValue(v.Key())
+		if err != nil {
+			return "", agnostic.InValue("key", err)
+		}
+
+		return mapValue + ".get(" + key + ")", nil
 	case value.Combined:
-		return resolveValue(v.Left()) + " " + v.Operator().Value() + " " + resolveValue(v.Right())
+		left, err := resolveOperand(v.Left(), v.Operator(), false)
+		if err != nil {
+			return "", agnostic.InValue("left", err)
+		}
+
+		right, err := resolveOperand(v.Right(), v.Operator(), true)
+		if err != nil {
+			return "", agnostic.InValue("right", err)
+		}
+
+		return left + " " + v.Operator().Value() + " " + right, nil
+	case value.Unary:
+		operand, err := resolveValue(v.Value())
+		if err != nil {
+			return "", agnostic.InValue("value", err)
+		}
+
+		if needsUnaryParentheses(v.Value()) {
+			operand = "(" + operand + ")"
+		}
+
+		return v.Operator().Value() + operand, nil
 	case value.IntToString:
-		return "String(" + resolveValue(v.IntValue()) + ")"
+		intValue, err := resolveValue(v.IntValue())
+		if err != nil {
+			return "", agnostic.InValue("intValue", err)
+		}
+
+		return "String(" + intValue + ")", nil
+	case value.EnumValue:
+		return v.EnumName() + "." + v.ValueName(), nil
+	case value.Call:
+		arguments, err := resolveArguments(v.Arguments())
+		if err != nil {
+			return "", err
+		}
+
+		return v.Function() + "(" + arguments + ")", nil
+	case value.OwnMethodCall:
+		arguments, err := resolveArguments(v.Arguments())
+		if err != nil {
+			return "", err
+		}
+
+		return "this." + v.Method() + "(" + arguments + ")", nil
+	case value.MethodCall:
+		model, err := resolveValue(v.Model())
+		if err != nil {
+			return "", agnostic.InValue("model", err)
+		}
+
+		arguments, err := resolveArguments(v.Arguments())
+		if err != nil {
+			return "", err
+		}
+
+		return model + "." + v.Method() + "(" + arguments + ")", nil
 	default:
-		panic(errors.New(fmt.Sprintf("uknown type %T", v)))
+		return "", fmt.Errorf("unsupported value %T in typescript", v)
+	}
+}
+
+// The precedence of each operator in TypeScript. Operators with a higher
+// precedence bind more tightly
+var precedence = map[value.Operator]int{
+	value.Multiply:           6,
+	value.Divide:             6,
+	value.Modulo:             6,
+	value.Add:                5,
+	value.Subtract:           5,
+	value.GreatThan:          4,
+	value.GreatThanOrEqualTo: 4,
+	value.LessThan:           4,
+	value.LassThanOrEqualTo:  4,
+	value.Equal:              3,
+	value.NotEqual:           3,
+	value.And:                2,
+	value.Or:                 1,
+}
+
+// Resolves a value that one side of the operator is applied to. Combined values
+// are wrapped in parentheses whenever the precedence of the operators would
+// otherwise group them differently than the agnostic tree does. Every operator
+// is left associative so a right operand with the same precedence needs them
+// too
+func resolveOperand(operand value.Any, operator value.Operator, isRight bool) (string, error) {
+	resolved, err := resolveValue(operand)
+	if err != nil {
+		return "", err
+	}
+
+	if combined, ok := operand.(value.Combined); ok {
+		operandPrecedence := precedence[combined.Operator()]
+		if operandPrecedence < precedence[operator] || (isRight && operandPrecedence == precedence[operator]) {
+			return "(" + resolved + ")", nil
+		}
 	}
+
+	return resolved, nil
+}
+
+// Whether the value has to be wrapped in parentheses to have a unary operator
+// applied to it. Negative literals are included so that negating them doesn't
+// produce a decrement operator
+func needsUnaryParentheses(operand value.Any) bool {
+	switch v := operand.(type) {
+	case value.Combined, value.Unary:
+		return true
+	case value.Int:
+		return v.Value() < 0
+	case value.Float:
+		return v.Value() < 0
+	default:
+		return false
+	}
+}
+
+// Resolves the arguments of a call as a comma separated list
+func resolveArguments(arguments []value.Any) (string, error) {
+	var sb strings.Builder
+	for i, argument := range arguments {
+		resolved, err := resolveValue(argument)
+		if err != nil {
+			return "", agnostic.InValue("arguments["+strconv.Itoa(i)+"]", err)
+		}
+
+		sb.WriteString(resolved)
+
+		if i+1 != len(arguments) {
+			sb.WriteString(", ")
+		}
+	}
+
+	return sb.String(), nil
 }
@@ -0,0 +1,109 @@
+package typescript
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestErrorsAreCollected(t *testing.T) {
+	implementation, err := NewImplementation(map[string]string{})
+	require.NoError(t, err)
+
+	implementation.Model("TestModel", agnostic.Field{Name: "pointer", Type: types.NewPointer(types.BaseInt)})
+	implementation.Method("TestModel", "MapPut", agnostic.Field{Name: "value", Type: types.NewPointer(types.BaseInt)})
+	body := implementation.Method("TestModel", "Valid")
+	body.Declare("values", value.NewMap(types.BaseInt, types.NewPointer(types.BaseInt)))
+	body.ForEachMapSorted(value.NewId("flags"), types.BaseBool, "key", "value")
+	body.MapPut(value.NewId("values"), value.NewCombined(value.NewInt(1), value.Add, value.NewMap(types.BaseInt, types.NewPointer(types.BaseInt))), value.NewInt(1))
+	implementation.Method("MissingModel", "Orphan")
+
+	var contents strings.Builder
+	_, err = implementation.WriteTo(&contents)
+	require.Error(t, err)
+	require.Equal(t, strings.Join([]string{
+		"TestModel.pointer: unsupported type types.Pointer in typescript",
+		"TestModel.MapPut: unsupported type types.Pointer in typescript",
+		"TestModel.Valid: Declare.value: unsupported type types.Pointer in typescript",
+		"TestModel.Valid: map keys of type bool can't be sorted",
+		"TestModel.Valid: MapPut.key.right: unsupported type types.Pointer in typescript",
+		"MissingModel.Orphan: no model with name \"MissingModel\" found for method",
+	}, "\n"), err.Error())
+	require.Empty(t, contents.String())
+}
+
+func TestCallsAreChecked(t *testing.T) {
+	implementation, err := NewImplementation(map[string]string{})
+	require.NoError(t, err)
+
+	implementation.Model("TestModel", agnostic.Field{Name: "count", Type: types.BaseInt, Default: value.NewCall("makeCount")})
+	body := implementation.Method("TestModel", "Caller")
+	body.Call(value.NewOwnMethodCall("Later", value.NewString("wrong")))
+	body.Call(value.NewCall("missing"))
+	implementation.Method("TestModel", "Later", agnostic.Field{Name: "count", Type: types.BaseInt})
+	implementation.Function("Free", nil).Call(value.NewOwnMethodCall("Later", value.NewInt(1)))
+	typed := implementation.Method("TestModel", "Typed", agnostic.Field{Name: "name", Type: types.BaseString})
+	typed.Call(value.NewOwnMethodCall("Later", value.NewId("name")))
+	typed.Declare("copy", value.NewOwnField(value.NewId("count")))
+	typed.Call(value.NewOwnMethodCall("Later", value.NewId("copy")))
+	typed.Call(value.NewMethodCall(value.NewOwnField(value.NewId("count")), types.NewModel("TestModel"), "Later", value.NewId("copy")))
+
+	var contents strings.Builder
+	_, err = implementation.WriteTo(&contents)
+	require.Error(t, err)
+	require.Equal(t, strings.Join([]string{
+		"Free: Call.call: method dependent value used outside of a method",
+		"TestModel.count: call to undeclared method or function \"makeCount\"",
+		"TestModel.Caller: argument \"count\" of \"TestModel.Later\" can't be a value.String",
+		"TestModel.Caller: call to undeclared method or function \"missing\"",
+		"TestModel.Typed: argument \"count\" of \"TestModel.Later\" can't be a value of type string",
+		"TestModel.Typed: \"TestModel.Later\" is called on a value of type int",
+	}, "\n"), err.Error())
+	require.Empty(t, contents.String())
+}
+
+func TestFailedWriteKeepsExistingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "agnostic")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "out")
+	require.NoError(t, ioutil.WriteFile(fileName+".ts", []byte("existing"), 0644))
+
+	implementation, err := NewImplementation(map[string]string{})
+	require.NoError(t, err)
+	implementation.Model("TestModel", agnostic.Field{Name: "pointer", Type: types.NewPointer(types.BaseInt)})
+
+	require.Error(t, implementation.Write(fileName))
+
+	contents, err := ioutil.ReadFile(fileName + ".ts")
+	require.NoError(t, err)
+	require.Equal(t, "existing", string(contents))
+}
+
+func TestRecursiveModelFields(t *testing.T) {
+	implementation, err := NewImplementation(map[string]string{})
+	require.NoError(t, err)
+
+	implementation.Model("Node",
+		agnostic.Field{Name: "next", Type: types.NewModel("Node")},
+		agnostic.Field{Name: "owner", Type: types.NewModel("Owner")},
+		agnostic.Field{Name: "color", Type: types.NewModel("Color")},
+	)
+	implementation.Model("Owner", agnostic.Field{Name: "root", Type: types.NewModel("Node")})
+	implementation.Enum("Color", "Red")
+
+	var contents strings.Builder
+	_, err = implementation.WriteTo(&contents)
+	require.NoError(t, err)
+	require.NotContains(t, contents.String(), "this.next")
+	require.NotContains(t, contents.String(), "this.owner")
+	require.NotContains(t, contents.String(), "this.root")
+	require.Contains(t, contents.String(), "this.color = 0;")
+}
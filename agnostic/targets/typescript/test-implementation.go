@@ -1,14 +1,15 @@
 package typescript
 
 import (
-	"bufio"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/test"
-	"os"
+	"io"
+	"sort"
 	"strings"
 )
 
-const TestPreamble = `import {TestModel} from "./generated";
-import * as assert from "assert";
+const TestPreamble = `import * as assert from "assert";
 describe('AgnosticTest', () => {
 `
 
@@ -17,6 +18,8 @@ const TestPostscript = "});\n"
 type TestImplementation struct {
 	code           strings.Builder
 	curIndentation int
+	enums          map[string]bool // Enums used by the facts that need to be imported
+	errors         agnostic.ErrorList
 }
 
 func (t *TestImplementation) IncreaseIndentation() {
@@ -33,50 +36,65 @@ func (t *TestImplementation) Add(line string) {
 	t.code.WriteString("\n")
 }
 
-func (t *TestImplementation) Write(fileName string) {
-	file, err := os.Create(fileName + ".ts")
-	if err != nil {
-		panic(err)
-	}
-
-	writer := bufio.NewWriter(file)
+func (t *TestImplementation) Write(fileName string) error {
+	return agnostic.WriteFile(fileName+".ts", t)
+}
 
-	_, err = writer.WriteString(TestPreamble)
-	if err != nil {
-		panic(err)
+func (t *TestImplementation) WriteTo(out io.Writer) (n int64, err error) {
+	if err = t.errors.Err(); err != nil {
+		return 0, err
 	}
 
-	_, err = writer.WriteString(t.code.String())
-	if err != nil {
-		panic(err)
+	imports := []string{"TestModel"}
+	for enum := range t.enums {
+		imports = append(imports, enum)
 	}
+	sort.Strings(imports[1:])
 
-	_, err = writer.WriteString(TestPostscript)
-	if err != nil {
-		panic(err)
-	}
+	importLine := "import {" + strings.Join(imports, ", ") + "} from \"./generated\";\n"
+	written, err := io.WriteString(out, importLine+TestPreamble+t.code.String()+TestPostscript)
+	return int64(written), err
+}
 
-	err = writer.Flush()
-	if err != nil {
-		panic(err)
+// Records the enums that the value refers to so that they can be imported
+func (t *TestImplementation) addEnums(any value.Any) {
+	switch v := any.(type) {
+	case value.EnumValue:
+		t.enums[v.EnumName()] = true
+	case value.Array:
+		for _, element := range v.Elements() {
+			t.addEnums(element)
+		}
+	case value.Map:
+		for _, element := range v.Elements() {
+			t.addEnums(element.Key())
+			t.addEnums(element.Value())
+		}
 	}
+}
 
-	err = file.Close()
+// Resolves the value while recording any error against the given location
+func (t *TestImplementation) resolveValue(location string, any value.Any) string {
+	resolved, err := resolveValue(any)
 	if err != nil {
-		panic(err)
+		t.errors.Add(location, err)
 	}
+	t.addEnums(any)
+
+	return resolved
 }
 
 func (t *TestImplementation) Test(testCase test.Case) {
 	for _, fact := range testCase.Facts {
-		t.Add("it('" + testCase.Name + "_" + fact.Name + " should work', () => {")
+		location := testCase.Name + "_" + fact.Name
+		t.Add("it('" + location + " should work', () => {")
 		t.IncreaseIndentation()
 
 		t.Add("const model = new TestModel();")
 
 		var inputs strings.Builder
 		for i, input := range fact.Inputs {
-			inputs.WriteString(resolveValue(input))
+			inputs.WriteString(t.resolveValue(location, input))
 
 			if i+1 != len(fact.Inputs) {
 				inputs.WriteString(", ")
@@ -85,13 +103,13 @@ func (t *TestImplementation) Test(testCase test.Case) {
 
 		if fact.Output != nil {
 			t.Add("const result = model." + testCase.Name + "(" + inputs.String() + ");")
-			t.Add("assert.deepStrictEqual(" + resolveValue(fact.Output) + ", result);")
+			t.Add("assert.deepStrictEqual(" + t.resolveValue(location, fact.Output) + ", result);")
 		} else {
 			t.Add("model." + testCase.Name + "(" + inputs.String() + ");")
 		}
 
 		for _, sideEffect := range fact.SideEffects {
-			t.Add("assert.deepStrictEqual(" + resolveValue(sideEffect.ExpectedValue) + ", model." + sideEffect.FieldName + ");")
+			t.Add("assert.deepStrictEqual(" + t.resolveValue(location, sideEffect.ExpectedValue) + ", model." + sideEffect.FieldName + ");")
 		}
 
 		t.DecreaseIndentation()
@@ -99,8 +117,9 @@ func (t *TestImplementation) Test(testCase test.Case) {
 	}
 }
 
-func NewTestImplementation(args map[string]string) test.Implementation {
+func NewTestImplementation(args map[string]string) (test.Implementation, error) {
 	return &TestImplementation{
 		curIndentation: 1,
-	}
+		enums:          make(map[string]bool),
+	}, nil
 }
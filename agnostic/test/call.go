@@ -0,0 +1,121 @@
+package test
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+var CallSuite = Suite{
+	{
+		Name:        "OwnMethodCall",
+		Description: "Support for calling another method of the same model",
+		Parameters: []agnostic.Field{
+			{Name: "value", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Dependencies: func(implementation agnostic.Implementation) {
+			addOneBody := implementation.ReturnMethod("TestModel", "AddOne", types.BaseInt, agnostic.Field{Name: "x", Type: types.BaseInt})
+			addOneBody.Return(value.NewCombined(value.NewId("x"), value.Add, value.NewInt(1)))
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(value.NewCombined(value.NewOwnMethodCall("AddOne", value.NewId("value")), value.Multiply, value.NewInt(2)))
+		},
+		Facts: []Fact{
+			{
+				Name:   "Positive",
+				Inputs: []value.Any{value.NewInt(1)},
+				Output: value.NewInt(4),
+			},
+		},
+	},
+	{
+		Name:        "OwnMethodCallStatement",
+		Description: "Support for calling another method of the same model as a statement",
+		ModelFields: []agnostic.Field{
+			{Name: "Counter", Type: types.BaseInt},
+		},
+		Dependencies: func(implementation agnostic.Implementation) {
+			counterValue := value.NewOwnField(value.NewId("Counter"))
+
+			incrementBody := implementation.Method("TestModel", "IncrementCounter")
+			incrementBody.Assign(counterValue, value.NewCombined(counterValue, value.Add, value.NewInt(1)))
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Assign(value.NewOwnField(value.NewId("Counter")), value.NewInt(0))
+			body.Call(value.NewOwnMethodCall("IncrementCounter"))
+			body.Call(value.NewOwnMethodCall("IncrementCounter"))
+		},
+		Facts: []Fact{
+			{
+				Name: "CalledTwice",
+				SideEffects: []SideEffect{
+					{FieldName: "Counter", ExpectedValue: value.NewInt(2)},
+				},
+			},
+		},
+	},
+	{
+		Name:        "NestedModelMethodCall",
+		Description: "Support for calling the methods of a model that is stored in a field",
+		ModelFields: []agnostic.Field{
+			{Name: "Counted", Type: types.NewModel("CountedModel")},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "amount", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Dependencies: func(implementation agnostic.Implementation) {
+			countValue := value.NewOwnField(value.NewId("Count"))
+
+			implementation.Model("CountedModel", agnostic.Field{Name: "Count", Type: types.BaseInt})
+
+			incrementBody := implementation.Method("CountedModel", "Increment", agnostic.Field{Name: "by", Type: types.BaseInt})
+			incrementBody.Assign(countValue, value.NewCombined(countValue, value.Add, value.NewId("by")))
+
+			doubledBody := implementation.ReturnMethod("CountedModel", "Doubled", types.BaseInt)
+			doubledBody.Return(value.NewCombined(countValue, value.Multiply, value.NewInt(2)))
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			countedValue := value.NewOwnField(value.NewId("Counted"))
+			countedType := types.NewModel("CountedModel")
+
+			body.Call(value.NewMethodCall(countedValue, countedType, "Increment", value.NewId("amount")))
+			body.Return(value.NewMethodCall(countedValue, countedType, "Doubled"))
+		},
+		Facts: []Fact{
+			{
+				Name:   "Increment",
+				Inputs: []value.Any{value.NewInt(3)},
+				Output: value.NewInt(6),
+			},
+		},
+	},
+	{
+		Name:        "FunctionCallStatement",
+		Description: "Support for calling a function as a statement",
+		Parameters: []agnostic.Field{
+			{Name: "mapInput", Type: types.NewMap(types.BaseInt, types.BaseString)},
+		},
+		Returns: types.NewMap(types.BaseInt, types.BaseString),
+		Dependencies: func(implementation agnostic.Implementation) {
+			putOneBody := implementation.Function("putOne", nil, agnostic.Field{Name: "m", Type: types.NewMap(types.BaseInt, types.BaseString)})
+			putOneBody.MapPut(value.NewId("m"), value.NewInt(1), value.NewString("one"))
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Call(value.NewCall("putOne", value.NewId("mapInput")))
+			body.Return(value.NewId("mapInput"))
+		},
+		Facts: []Fact{
+			{
+				Name: "EmptyMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseInt, types.BaseString),
+				},
+				Output: value.NewMap(types.BaseInt, types.BaseString,
+					value.NewKeyValue(value.NewInt(1), value.NewString("one")),
+				),
+			},
+		},
+	},
+}
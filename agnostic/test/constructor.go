@@ -0,0 +1,125 @@
+package test
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+var ConstructorSuite = Suite{
+	{
+		Name:        "DefaultValues",
+		Description: "Support for giving model fields a default value",
+		ModelFields: []agnostic.Field{
+			{Name: "DefaultInt", Type: types.BaseInt, Default: value.NewInt(5)},
+			{Name: "DefaultString", Type: types.BaseString, Default: value.NewString("default")},
+			{Name: "DefaultBool", Type: types.BaseBool, Default: value.NewBool(true)},
+			{Name: "DefaultArray", Type: types.NewArray(types.BaseInt), Default: value.NewArray(types.BaseInt, value.NewInt(1), value.NewInt(2))},
+		},
+		Generator: func(body agnostic.BodyImplementation) {},
+		Facts: []Fact{
+			{
+				Name: "NewModel",
+				SideEffects: []SideEffect{
+					{FieldName: "DefaultInt", ExpectedValue: value.NewInt(5)},
+					{FieldName: "DefaultString", ExpectedValue: value.NewString("default")},
+					{FieldName: "DefaultBool", ExpectedValue: value.NewBool(true)},
+					{FieldName: "DefaultArray", ExpectedValue: value.NewArray(types.BaseInt, value.NewInt(1), value.NewInt(2))},
+				},
+			},
+		},
+	},
+	{
+		Name:        "EmptyCollections",
+		Description: "Support for array and map fields starting empty",
+		ModelFields: []agnostic.Field{
+			{Name: "EmptyArray", Type: types.NewArray(types.BaseInt)},
+			{Name: "EmptyMap", Type: types.NewMap(types.BaseInt, types.BaseString)},
+		},
+		Generator: func(body agnostic.BodyImplementation) {},
+		Facts: []Fact{
+			{
+				Name: "NewModel",
+				SideEffects: []SideEffect{
+					{FieldName: "EmptyArray", ExpectedValue: value.NewArray(types.BaseInt)},
+					{FieldName: "EmptyMap", ExpectedValue: value.NewMap(types.BaseInt, types.BaseString)},
+				},
+			},
+		},
+	},
+	{
+		Name:        "CollectionsOnNewModel",
+		Description: "Support for modifying the array and map fields of a new model",
+		ModelFields: []agnostic.Field{
+			{Name: "Items", Type: types.NewMap(types.BaseInt, types.BaseString)},
+			{Name: "Keys", Type: types.NewArray(types.BaseInt)},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "key", Type: types.BaseInt},
+			{Name: "value", Type: types.BaseString},
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			body.MapPut(value.NewOwnField(value.NewId("Items")), value.NewId("key"), value.NewId("value"))
+			body.AppendValue(value.NewOwnField(value.NewId("Keys")), value.NewId("key"))
+		},
+		Facts: []Fact{
+			{
+				Name: "NewModel",
+				Inputs: []value.Any{
+					value.NewInt(1),
+					value.NewString("one"),
+				},
+				SideEffects: []SideEffect{
+					{
+						FieldName: "Items",
+						ExpectedValue: value.NewMap(types.BaseInt, types.BaseString,
+							value.NewKeyValue(value.NewInt(1), value.NewString("one")),
+						),
+					},
+					{FieldName: "Keys", ExpectedValue: value.NewArray(types.BaseInt, value.NewInt(1))},
+				},
+			},
+		},
+	},
+	{
+		Name:        "NestedModel",
+		Description: "Support for model fields starting as a new model",
+		ModelFields: []agnostic.Field{
+			{Name: "Child", Type: types.NewModel("ChildModel")},
+		},
+		Returns: types.BaseInt,
+		Dependencies: func(implementation agnostic.Implementation) {
+			implementation.Model("ChildModel", agnostic.Field{Name: "Count", Type: types.BaseInt, Default: value.NewInt(1)})
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(value.NewOwnField(value.NewModelField("Child", value.NewId("Count"))))
+		},
+		Facts: []Fact{
+			{
+				Name:   "NewModel",
+				Output: value.NewInt(1),
+			},
+		},
+	},
+	{
+		Name:        "EnumField",
+		Description: "Support for enum fields starting as the first value of the enum",
+		ModelFields: []agnostic.Field{
+			{Name: "Status", Type: types.NewModel("Status")},
+		},
+		Returns: types.BaseBool,
+		Dependencies: func(implementation agnostic.Implementation) {
+			// Declared after TestModel so the constructor can't know about it up front
+			implementation.Enum("Status", "Active", "Inactive")
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(value.NewCombined(value.NewOwnField(value.NewId("Status")), value.Equal, value.NewEnumValue("Status", "Active")))
+		},
+		Facts: []Fact{
+			{
+				Name:   "NewModel",
+				Output: value.NewBool(true),
+			},
+		},
+	},
+}
@@ -195,4 +195,65 @@ var ForSuite = Suite{
 			},
 		},
 	},
+	{
+		Name:        "ForEachBreak",
+		Description: "Support for exiting a foreach loop early",
+		Parameters: []agnostic.Field{
+			{Name: "arrayInput", Type: types.NewArray(types.BaseInt)},
+		},
+		Returns: types.BaseInt,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Declare("sum", value.NewInt(0))
+
+			forEachBody := body.ForEach(value.NewId("arrayInput"), "", "value")
+			ifBody := forEachBody.If(value.NewCombined(value.NewId("value"), value.LessThan, value.NewInt(0)))
+			ifBody.Break()
+			forEachBody.Assign(value.NewId("sum"), value.NewCombined(value.NewId("sum"), value.Add, value.NewId("value")))
+
+			body.Return(value.NewId("sum"))
+		},
+		Facts: []Fact{
+			{
+				Name: "NoBreak",
+				Inputs: []value.Any{
+					value.NewArray(types.BaseInt, value.NewInt(1), value.NewInt(2), value.NewInt(3)),
+				},
+				Output: value.NewInt(6),
+			},
+			{
+				Name: "Break",
+				Inputs: []value.Any{
+					value.NewArray(types.BaseInt, value.NewInt(1), value.NewInt(2), value.NewInt(-1), value.NewInt(5)),
+				},
+				Output: value.NewInt(3),
+			},
+		},
+	},
+	{
+		Name:        "ForEachContinue",
+		Description: "Support for skipping an iteration of a foreach loop",
+		Parameters: []agnostic.Field{
+			{Name: "arrayInput", Type: types.NewArray(types.BaseInt)},
+		},
+		Returns: types.BaseInt,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Declare("sum", value.NewInt(0))
+
+			forEachBody := body.ForEach(value.NewId("arrayInput"), "index", "value")
+			ifBody := forEachBody.If(value.NewCombined(value.NewId("value"), value.LessThan, value.NewInt(0)))
+			ifBody.Continue()
+			forEachBody.Assign(value.NewId("sum"), value.NewCombined(value.NewId("sum"), value.Add, value.NewId("index")))
+
+			body.Return(value.NewId("sum"))
+		},
+		Facts: []Fact{
+			{
+				Name: "SkipNegatives",
+				Inputs: []value.Any{
+					value.NewArray(types.BaseInt, value.NewInt(1), value.NewInt(-2), value.NewInt(3), value.NewInt(-4)),
+				},
+				Output: value.NewInt(2),
+			},
+		},
+	},
 }
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+var FunctionSuite = Suite{
+	{
+		Name:        "Function",
+		Description: "Support for calling a function that isn't bound to a model",
+		Parameters: []agnostic.Field{
+			{Name: "value", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Dependencies: func(implementation agnostic.Implementation) {
+			squareBody := implementation.Function("square", types.BaseInt, agnostic.Field{Name: "x", Type: types.BaseInt})
+			squareBody.Return(value.NewCombined(value.NewId("x"), value.Multiply, value.NewId("x")))
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(value.NewCall("square", value.NewId("value")))
+		},
+		Facts: []Fact{
+			{
+				Name:   "Positive",
+				Inputs: []value.Any{value.NewInt(3)},
+				Output: value.NewInt(9),
+			},
+			{
+				Name:   "Negative",
+				Inputs: []value.Any{value.NewInt(-4)},
+				Output: value.NewInt(16),
+			},
+		},
+	},
+	{
+		Name:        "FunctionCallingFunction",
+		Description: "Support for calling a function from within another function",
+		ModelFields: []agnostic.Field{
+			{Name: "Quadrupled", Type: types.BaseInt},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "value", Type: types.BaseInt},
+		},
+		Dependencies: func(implementation agnostic.Implementation) {
+			doubleBody := implementation.Function("double", types.BaseInt, agnostic.Field{Name: "x", Type: types.BaseInt})
+			doubleBody.Return(value.NewCombined(value.NewId("x"), value.Add, value.NewId("x")))
+
+			quadrupleBody := implementation.Function("quadruple", types.BaseInt, agnostic.Field{Name: "x", Type: types.BaseInt})
+			quadrupleBody.Declare("doubled", value.NewCall("double", value.NewId("x")))
+			quadrupleBody.Return(value.NewCall("double", value.NewId("doubled")))
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Assign(value.NewOwnField(value.NewId("Quadrupled")), value.NewCall("quadruple", value.NewId("value")))
+		},
+		Facts: []Fact{
+			{
+				Name:   "Quadrupled",
+				Inputs: []value.Any{value.NewInt(3)},
+				SideEffects: []SideEffect{
+					{FieldName: "Quadrupled", ExpectedValue: value.NewInt(12)},
+				},
+			},
+		},
+	},
+}
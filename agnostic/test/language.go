@@ -1,7 +1,10 @@
 package test
 
+import "io"
+
 // A test file in an arbitrary programming language
 type Implementation interface {
-	Write(fileName string)
+	Write(fileName string) error
+	WriteTo(out io.Writer) (n int64, err error)
 	Test(testCase Case)
 }
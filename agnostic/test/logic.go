@@ -0,0 +1,236 @@
+package test
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+// Creates a method that records that it was called and then returns the given
+// result. Used to check whether the right side of a logical operator is run
+func generateMarkEvaluated(methodName string, result bool) GenerateDependenciesFunc {
+	return func(implementation agnostic.Implementation) {
+		body := implementation.ReturnMethod("TestModel", methodName, types.BaseBool)
+		body.Assign(value.NewOwnField(value.NewId("Evaluated")), value.NewBool(true))
+		body.Return(value.NewBool(result))
+	}
+}
+
+var LogicSuite = Suite{
+	{
+		Name:        "And",
+		Description: "Support for the logical and operator",
+		Parameters: []agnostic.Field{
+			{Name: "a", Type: types.BaseBool},
+			{Name: "b", Type: types.BaseBool},
+		},
+		Returns: types.BaseBool,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(value.NewCombined(value.NewId("a"), value.And, value.NewId("b")))
+		},
+		Facts: []Fact{
+			{
+				Name:   "BothTrue",
+				Inputs: []value.Any{value.NewBool(true), value.NewBool(true)},
+				Output: value.NewBool(true),
+			},
+			{
+				Name:   "OneFalse",
+				Inputs: []value.Any{value.NewBool(true), value.NewBool(false)},
+				Output: value.NewBool(false),
+			},
+		},
+	},
+	{
+		Name:        "Or",
+		Description: "Support for the logical or operator",
+		Parameters: []agnostic.Field{
+			{Name: "a", Type: types.BaseBool},
+			{Name: "b", Type: types.BaseBool},
+		},
+		Returns: types.BaseBool,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(value.NewCombined(value.NewId("a"), value.Or, value.NewId("b")))
+		},
+		Facts: []Fact{
+			{
+				Name:   "OneTrue",
+				Inputs: []value.Any{value.NewBool(false), value.NewBool(true)},
+				Output: value.NewBool(true),
+			},
+			{
+				Name:   "BothFalse",
+				Inputs: []value.Any{value.NewBool(false), value.NewBool(false)},
+				Output: value.NewBool(false),
+			},
+		},
+	},
+	{
+		Name:        "Not",
+		Description: "Support for the logical not operator",
+		Parameters: []agnostic.Field{
+			{Name: "a", Type: types.BaseBool},
+			{Name: "b", Type: types.BaseBool},
+		},
+		Returns: types.BaseBool,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(value.NewUnary(value.Not, value.NewCombined(value.NewId("a"), value.And, value.NewId("b"))))
+		},
+		Facts: []Fact{
+			{
+				Name:   "BothTrue",
+				Inputs: []value.Any{value.NewBool(true), value.NewBool(true)},
+				Output: value.NewBool(false),
+			},
+			{
+				Name:   "OneFalse",
+				Inputs: []value.Any{value.NewBool(false), value.NewBool(true)},
+				Output: value.NewBool(true),
+			},
+		},
+	},
+	{
+		Name:        "Negate",
+		Description: "Support for the unary minus operator",
+		Parameters: []agnostic.Field{
+			{Name: "value", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Generator: func(body agnostic.BodyImplementation) {
+			negatedSum := value.NewUnary(value.Negate, value.NewCombined(value.NewId("value"), value.Add, value.NewInt(1)))
+			body.Return(value.NewCombined(negatedSum, value.Subtract, value.NewUnary(value.Negate, value.NewInt(-2))))
+		},
+		Facts: []Fact{
+			{
+				Name:   "Positive",
+				Inputs: []value.Any{value.NewInt(2)},
+				Output: value.NewInt(-5),
+			},
+			{
+				Name:   "Negative",
+				Inputs: []value.Any{value.NewInt(-4)},
+				Output: value.NewInt(1),
+			},
+		},
+	},
+	{
+		Name:        "MixedLogic",
+		Description: "Support for nesting logical operators",
+		Parameters: []agnostic.Field{
+			{Name: "a", Type: types.BaseBool},
+			{Name: "b", Type: types.BaseBool},
+			{Name: "c", Type: types.BaseBool},
+		},
+		Returns: types.BaseBool,
+		Generator: func(body agnostic.BodyImplementation) {
+			aOrB := value.NewCombined(value.NewId("a"), value.Or, value.NewId("b"))
+			body.Return(value.NewCombined(aOrB, value.And, value.NewId("c")))
+		},
+		Facts: []Fact{
+			{
+				// Would be true if the or was applied last
+				Name:   "LeftTrue",
+				Inputs: []value.Any{value.NewBool(true), value.NewBool(false), value.NewBool(false)},
+				Output: value.NewBool(false),
+			},
+			{
+				Name:   "AllTrue",
+				Inputs: []value.Any{value.NewBool(true), value.NewBool(true), value.NewBool(true)},
+				Output: value.NewBool(true),
+			},
+		},
+	},
+	{
+		Name:        "LogicWithComparisons",
+		Description: "Support for combining comparisons with logical operators",
+		Parameters: []agnostic.Field{
+			{Name: "value", Type: types.BaseInt},
+		},
+		Returns: types.BaseBool,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(value.NewCombined(
+				value.NewCombined(value.NewId("value"), value.GreatThan, value.NewInt(0)),
+				value.And,
+				value.NewCombined(value.NewCombined(value.NewId("value"), value.Modulo, value.NewInt(2)), value.Equal, value.NewInt(0)),
+			))
+		},
+		Facts: []Fact{
+			{
+				Name:   "PositiveEven",
+				Inputs: []value.Any{value.NewInt(4)},
+				Output: value.NewBool(true),
+			},
+			{
+				Name:   "NegativeEven",
+				Inputs: []value.Any{value.NewInt(-4)},
+				Output: value.NewBool(false),
+			},
+		},
+	},
+	{
+		Name:        "AndShortCircuit",
+		Description: "Support for skipping the right side of an and when the left side is false",
+		ModelFields: []agnostic.Field{
+			{Name: "Evaluated", Type: types.BaseBool},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "condition", Type: types.BaseBool},
+		},
+		Returns:      types.BaseBool,
+		Dependencies: generateMarkEvaluated("MarkEvaluatedTrue", true),
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(value.NewCombined(value.NewId("condition"), value.And, value.NewOwnMethodCall("MarkEvaluatedTrue")))
+		},
+		Facts: []Fact{
+			{
+				Name:   "LeftFalse",
+				Inputs: []value.Any{value.NewBool(false)},
+				Output: value.NewBool(false),
+				SideEffects: []SideEffect{
+					{FieldName: "Evaluated", ExpectedValue: value.NewBool(false)},
+				},
+			},
+			{
+				Name:   "LeftTrue",
+				Inputs: []value.Any{value.NewBool(true)},
+				Output: value.NewBool(true),
+				SideEffects: []SideEffect{
+					{FieldName: "Evaluated", ExpectedValue: value.NewBool(true)},
+				},
+			},
+		},
+	},
+	{
+		Name:        "OrShortCircuit",
+		Description: "Support for skipping the right side of an or when the left side is true",
+		ModelFields: []agnostic.Field{
+			{Name: "Evaluated", Type: types.BaseBool},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "condition", Type: types.BaseBool},
+		},
+		Returns:      types.BaseBool,
+		Dependencies: generateMarkEvaluated("MarkEvaluatedFalse", false),
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(value.NewCombined(value.NewId("condition"), value.Or, value.NewOwnMethodCall("MarkEvaluatedFalse")))
+		},
+		Facts: []Fact{
+			{
+				Name:   "LeftTrue",
+				Inputs: []value.Any{value.NewBool(true)},
+				Output: value.NewBool(true),
+				SideEffects: []SideEffect{
+					{FieldName: "Evaluated", ExpectedValue: value.NewBool(false)},
+				},
+			},
+			{
+				Name:   "LeftFalse",
+				Inputs: []value.Any{value.NewBool(false)},
+				Output: value.NewBool(false),
+				SideEffects: []SideEffect{
+					{FieldName: "Evaluated", ExpectedValue: value.NewBool(true)},
+				},
+			},
+		},
+	},
+}
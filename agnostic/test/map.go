@@ -91,4 +91,287 @@ var MapSuite = Suite{
 			},
 		},
 	},
+	{
+		Name:        "ForEachMapKeyAndValue",
+		Description: "Support for iterating through a map with both a key and value variable",
+		ModelFields: []agnostic.Field{
+			{Name: "SumKeys", Type: types.BaseInt},
+			{Name: "SumValues", Type: types.BaseInt},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "mapInput", Type: types.NewMap(types.BaseInt, types.BaseInt)},
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			sumKeysValue := value.NewOwnField(value.NewId("SumKeys"))
+			sumValuesValue := value.NewOwnField(value.NewId("SumValues"))
+
+			body.Assign(sumKeysValue, value.NewInt(0))
+			body.Assign(sumValuesValue, value.NewInt(0))
+
+			forEachBody := body.ForEachMap(value.NewId("mapInput"), "key", "value")
+			forEachBody.Assign(sumKeysValue, value.NewCombined(sumKeysValue, value.Add, value.NewId("key")))
+			forEachBody.Assign(sumValuesValue, value.NewCombined(sumValuesValue, value.Add, value.NewId("value")))
+		},
+		Facts: []Fact{
+			{
+				Name: "EmptyMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseInt, types.BaseInt),
+				},
+				SideEffects: []SideEffect{
+					{FieldName: "SumKeys", ExpectedValue: value.NewInt(0)},
+					{FieldName: "SumValues", ExpectedValue: value.NewInt(0)},
+				},
+			},
+			{
+				Name: "PopulatedMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseInt, types.BaseInt,
+						value.NewKeyValue(value.NewInt(1), value.NewInt(10)),
+						value.NewKeyValue(value.NewInt(2), value.NewInt(20)),
+						value.NewKeyValue(value.NewInt(3), value.NewInt(30)),
+					),
+				},
+				SideEffects: []SideEffect{
+					{FieldName: "SumKeys", ExpectedValue: value.NewInt(6)},
+					{FieldName: "SumValues", ExpectedValue: value.NewInt(60)},
+				},
+			},
+		},
+	},
+	{
+		Name:        "ForEachMapKeyOnly",
+		Description: "Support for iterating through a map with a key variable but no value variable",
+		ModelFields: []agnostic.Field{
+			{Name: "SumKeys", Type: types.BaseInt},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "mapInput", Type: types.NewMap(types.BaseInt, types.BaseInt)},
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			sumKeysValue := value.NewOwnField(value.NewId("SumKeys"))
+
+			body.Assign(sumKeysValue, value.NewInt(0))
+
+			forEachBody := body.ForEachMap(value.NewId("mapInput"), "key", "")
+			forEachBody.Assign(sumKeysValue, value.NewCombined(sumKeysValue, value.Add, value.NewId("key")))
+		},
+		Facts: []Fact{
+			{
+				Name: "PopulatedMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseInt, types.BaseInt,
+						value.NewKeyValue(value.NewInt(1), value.NewInt(10)),
+						value.NewKeyValue(value.NewInt(2), value.NewInt(20)),
+					),
+				},
+				SideEffects: []SideEffect{
+					{FieldName: "SumKeys", ExpectedValue: value.NewInt(3)},
+				},
+			},
+		},
+	},
+	{
+		Name:        "ForEachMapValueOnly",
+		Description: "Support for iterating through a map with a value variable but no key variable",
+		ModelFields: []agnostic.Field{
+			{Name: "SumValues", Type: types.BaseInt},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "mapInput", Type: types.NewMap(types.BaseInt, types.BaseInt)},
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			sumValuesValue := value.NewOwnField(value.NewId("SumValues"))
+
+			body.Assign(sumValuesValue, value.NewInt(0))
+
+			forEachBody := body.ForEachMap(value.NewId("mapInput"), "", "value")
+			forEachBody.Assign(sumValuesValue, value.NewCombined(sumValuesValue, value.Add, value.NewId("value")))
+		},
+		Facts: []Fact{
+			{
+				Name: "PopulatedMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseInt, types.BaseInt,
+						value.NewKeyValue(value.NewInt(1), value.NewInt(10)),
+						value.NewKeyValue(value.NewInt(2), value.NewInt(20)),
+					),
+				},
+				SideEffects: []SideEffect{
+					{FieldName: "SumValues", ExpectedValue: value.NewInt(30)},
+				},
+			},
+		},
+	},
+	{
+		Name:        "ForEachMapNoVariables",
+		Description: "Support for iterating through a map without a key or value variable",
+		ModelFields: []agnostic.Field{
+			{Name: "Count", Type: types.BaseInt},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "mapInput", Type: types.NewMap(types.BaseInt, types.BaseInt)},
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			countValue := value.NewOwnField(value.NewId("Count"))
+
+			body.Assign(countValue, value.NewInt(0))
+
+			forEachBody := body.ForEachMap(value.NewId("mapInput"), "", "")
+			forEachBody.Assign(countValue, value.NewCombined(countValue, value.Add, value.NewInt(1)))
+		},
+		Facts: []Fact{
+			{
+				Name: "PopulatedMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseInt, types.BaseInt,
+						value.NewKeyValue(value.NewInt(1), value.NewInt(10)),
+						value.NewKeyValue(value.NewInt(2), value.NewInt(20)),
+					),
+				},
+				SideEffects: []SideEffect{
+					{FieldName: "Count", ExpectedValue: value.NewInt(2)},
+				},
+			},
+		},
+	},
+	{
+		Name:        "ForEachMapSorted",
+		Description: "Support for iterating through a map in ascending order of its integer keys",
+		ModelFields: []agnostic.Field{
+			{Name: "Result", Type: types.BaseString},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "mapInput", Type: types.NewMap(types.BaseInt, types.BaseString)},
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			resultValue := value.NewOwnField(value.NewId("Result"))
+
+			body.Assign(resultValue, value.NewString(""))
+
+			forEachBody := body.ForEachMapSorted(value.NewId("mapInput"), types.BaseInt, "key", "value")
+			forEachBody.Assign(resultValue, value.NewCombined(resultValue, value.Add, value.NewIntToString(value.NewId("key"))))
+			forEachBody.Assign(resultValue, value.NewCombined(resultValue, value.Add, value.NewId("value")))
+		},
+		Facts: []Fact{
+			{
+				Name: "EmptyMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseInt, types.BaseString),
+				},
+				SideEffects: []SideEffect{
+					{FieldName: "Result", ExpectedValue: value.NewString("")},
+				},
+			},
+			{
+				// 10 would come before 2 if the keys were compared as strings
+				Name: "PopulatedMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseInt, types.BaseString,
+						value.NewKeyValue(value.NewInt(3), value.NewString("c")),
+						value.NewKeyValue(value.NewInt(10), value.NewString("j")),
+						value.NewKeyValue(value.NewInt(1), value.NewString("a")),
+						value.NewKeyValue(value.NewInt(2), value.NewString("b")),
+					),
+				},
+				SideEffects: []SideEffect{
+					{FieldName: "Result", ExpectedValue: value.NewString("1a2b3c10j")},
+				},
+			},
+		},
+	},
+	{
+		Name:        "ForEachMapSortedKeyOnly",
+		Description: "Support for iterating through a map in ascending order of its string keys without a value variable",
+		ModelFields: []agnostic.Field{
+			{Name: "Result", Type: types.BaseString},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "mapInput", Type: types.NewMap(types.BaseString, types.BaseInt)},
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			resultValue := value.NewOwnField(value.NewId("Result"))
+
+			body.Assign(resultValue, value.NewString(""))
+
+			forEachBody := body.ForEachMapSorted(value.NewId("mapInput"), types.BaseString, "key", "")
+			forEachBody.Assign(resultValue, value.NewCombined(resultValue, value.Add, value.NewId("key")))
+		},
+		Facts: []Fact{
+			{
+				Name: "PopulatedMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseString, types.BaseInt,
+						value.NewKeyValue(value.NewString("b"), value.NewInt(2)),
+						value.NewKeyValue(value.NewString("c"), value.NewInt(3)),
+						value.NewKeyValue(value.NewString("a"), value.NewInt(1)),
+					),
+				},
+				SideEffects: []SideEffect{
+					{FieldName: "Result", ExpectedValue: value.NewString("abc")},
+				},
+			},
+		},
+	},
+	{
+		Name:        "ForEachMapSortedValueOnly",
+		Description: "Support for iterating through a map in ascending order of its keys without a key variable",
+		ModelFields: []agnostic.Field{
+			{Name: "Result", Type: types.BaseString},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "mapInput", Type: types.NewMap(types.BaseInt, types.BaseString)},
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			resultValue := value.NewOwnField(value.NewId("Result"))
+
+			body.Assign(resultValue, value.NewString(""))
+
+			forEachBody := body.ForEachMapSorted(value.NewId("mapInput"), types.BaseInt, "", "value")
+			forEachBody.Assign(resultValue, value.NewCombined(resultValue, value.Add, value.NewId("value")))
+		},
+		Facts: []Fact{
+			{
+				Name: "PopulatedMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseInt, types.BaseString,
+						value.NewKeyValue(value.NewInt(2), value.NewString("b")),
+						value.NewKeyValue(value.NewInt(3), value.NewString("c")),
+						value.NewKeyValue(value.NewInt(1), value.NewString("a")),
+					),
+				},
+				SideEffects: []SideEffect{
+					{FieldName: "Result", ExpectedValue: value.NewString("abc")},
+				},
+			},
+		},
+	},
+	{
+		Name:        "ForEachMapSortedOuterKey",
+		Description: "Support for using a variable named key inside of a sorted map loop without a key variable",
+		Parameters: []agnostic.Field{
+			{Name: "mapInput", Type: types.NewMap(types.BaseInt, types.BaseInt)},
+			{Name: "key", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Generator: func(body agnostic.BodyImplementation) {
+			forEachBody := body.ForEachMapSorted(value.NewId("mapInput"), types.BaseInt, "", "value")
+			forEachBody.Assign(value.NewId("key"), value.NewCombined(value.NewId("key"), value.Add, value.NewId("value")))
+
+			body.Return(value.NewId("key"))
+		},
+		Facts: []Fact{
+			{
+				Name: "PopulatedMap",
+				Inputs: []value.Any{
+					value.NewMap(types.BaseInt, types.BaseInt,
+						value.NewKeyValue(value.NewInt(1), value.NewInt(10)),
+						value.NewKeyValue(value.NewInt(2), value.NewInt(20)),
+					),
+					value.NewInt(1),
+				},
+				Output: value.NewInt(31),
+			},
+		},
+	},
 }
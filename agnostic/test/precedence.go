@@ -0,0 +1,157 @@
+package test
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+// Creates a case that returns the given expression of the int parameters a, b,
+// and c. Each fact's output is what the agnostic tree evaluates to, which often
+// differs from what the expression would evaluate to without parentheses
+func precedenceCase(name, description string, returns types.Any, expression value.Any, facts ...Fact) Case {
+	return Case{
+		Name:        name,
+		Description: description,
+		Parameters: []agnostic.Field{
+			{Name: "a", Type: types.BaseInt},
+			{Name: "b", Type: types.BaseInt},
+			{Name: "c", Type: types.BaseInt},
+		},
+		Returns: returns,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Return(expression)
+		},
+		Facts: facts,
+	}
+}
+
+var PrecedenceSuite = Suite{
+	precedenceCase(
+		"SumTimes",
+		"Support for multiplying a sum",
+		types.BaseInt,
+		value.NewCombined(value.NewCombined(value.NewId("a"), value.Add, value.NewId("b")), value.Multiply, value.NewId("c")),
+		Fact{
+			Name:   "Positive",
+			Inputs: []value.Any{value.NewInt(1), value.NewInt(2), value.NewInt(3)},
+			Output: value.NewInt(9),
+		},
+	),
+	precedenceCase(
+		"TimesSum",
+		"Support for multiplying by a sum",
+		types.BaseInt,
+		value.NewCombined(value.NewId("a"), value.Multiply, value.NewCombined(value.NewId("b"), value.Add, value.NewId("c"))),
+		Fact{
+			Name:   "Positive",
+			Inputs: []value.Any{value.NewInt(2), value.NewInt(3), value.NewInt(4)},
+			Output: value.NewInt(14),
+		},
+	),
+	precedenceCase(
+		"ProductPlus",
+		"Support for adding to a product",
+		types.BaseInt,
+		value.NewCombined(value.NewCombined(value.NewId("a"), value.Multiply, value.NewId("b")), value.Add, value.NewId("c")),
+		Fact{
+			Name:   "Positive",
+			Inputs: []value.Any{value.NewInt(2), value.NewInt(3), value.NewInt(4)},
+			Output: value.NewInt(10),
+		},
+	),
+	precedenceCase(
+		"SubtractDifference",
+		"Support for subtracting a difference",
+		types.BaseInt,
+		value.NewCombined(value.NewId("a"), value.Subtract, value.NewCombined(value.NewId("b"), value.Subtract, value.NewId("c"))),
+		Fact{
+			Name:   "Positive",
+			Inputs: []value.Any{value.NewInt(10), value.NewInt(4), value.NewInt(3)},
+			Output: value.NewInt(9),
+		},
+	),
+	precedenceCase(
+		"DifferenceMinus",
+		"Support for subtracting from a difference",
+		types.BaseInt,
+		value.NewCombined(value.NewCombined(value.NewId("a"), value.Subtract, value.NewId("b")), value.Subtract, value.NewId("c")),
+		Fact{
+			Name:   "Positive",
+			Inputs: []value.Any{value.NewInt(10), value.NewInt(4), value.NewInt(3)},
+			Output: value.NewInt(3),
+		},
+	),
+	precedenceCase(
+		"DivideByProduct",
+		"Support for dividing by a product",
+		types.BaseInt,
+		value.NewCombined(value.NewId("a"), value.Divide, value.NewCombined(value.NewId("b"), value.Multiply, value.NewId("c"))),
+		Fact{
+			Name:   "Positive",
+			Inputs: []value.Any{value.NewInt(24), value.NewInt(2), value.NewInt(3)},
+			Output: value.NewInt(4),
+		},
+	),
+	precedenceCase(
+		"SumModulo",
+		"Support for the modulo of a sum",
+		types.BaseInt,
+		value.NewCombined(value.NewCombined(value.NewId("a"), value.Add, value.NewId("b")), value.Modulo, value.NewId("c")),
+		Fact{
+			Name:   "Positive",
+			Inputs: []value.Any{value.NewInt(5), value.NewInt(4), value.NewInt(4)},
+			Output: value.NewInt(1),
+		},
+	),
+	precedenceCase(
+		"CompareComparisons",
+		"Support for comparing the results of two comparisons",
+		types.BaseBool,
+		value.NewCombined(
+			value.NewCombined(value.NewId("a"), value.LessThan, value.NewId("b")),
+			value.Equal,
+			value.NewCombined(value.NewId("b"), value.LessThan, value.NewId("c")),
+		),
+		Fact{
+			Name:   "Different",
+			Inputs: []value.Any{value.NewInt(1), value.NewInt(2), value.NewInt(1)},
+			Output: value.NewBool(false),
+		},
+		Fact{
+			Name:   "Same",
+			Inputs: []value.Any{value.NewInt(1), value.NewInt(2), value.NewInt(3)},
+			Output: value.NewBool(true),
+		},
+	),
+	precedenceCase(
+		"AndOfOr",
+		"Support for the logical and of a logical or",
+		types.BaseBool,
+		value.NewCombined(
+			value.NewCombined(value.NewId("a"), value.GreatThan, value.NewInt(0)),
+			value.And,
+			value.NewCombined(
+				value.NewCombined(value.NewId("b"), value.GreatThan, value.NewInt(0)),
+				value.Or,
+				value.NewCombined(value.NewId("c"), value.GreatThan, value.NewInt(0)),
+			),
+		),
+		Fact{
+			Name:   "LeftFalse",
+			Inputs: []value.Any{value.NewInt(0), value.NewInt(0), value.NewInt(1)},
+			Output: value.NewBool(false),
+		},
+	),
+	precedenceCase(
+		"NegatedProduct",
+		"Support for multiplying a negated sum",
+		types.BaseInt,
+		value.NewCombined(value.NewUnary(value.Negate, value.NewCombined(value.NewId("a"), value.Add, value.NewId("b"))), value.Multiply, value.NewId("c")),
+		Fact{
+			Name:   "Positive",
+			Inputs: []value.Any{value.NewInt(1), value.NewInt(2), value.NewInt(3)},
+			Output: value.NewInt(-9),
+		},
+	),
+}
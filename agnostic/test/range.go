@@ -0,0 +1,95 @@
+package test
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+var ForRangeSuite = Suite{
+	{
+		Name:        "ForRange",
+		Description: "Support for a loop that counts through a range",
+		Parameters: []agnostic.Field{
+			{Name: "start", Type: types.BaseInt},
+			{Name: "end", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Declare("sum", value.NewInt(0))
+
+			forRangeBody := body.ForRange("i", value.NewId("start"), value.NewId("end"))
+			forRangeBody.Assign(value.NewId("sum"), value.NewCombined(value.NewId("sum"), value.Add, value.NewId("i")))
+
+			body.Return(value.NewId("sum"))
+		},
+		Facts: []Fact{
+			{
+				Name:   "EmptyRange",
+				Inputs: []value.Any{value.NewInt(3), value.NewInt(3)},
+				Output: value.NewInt(0),
+			},
+			{
+				Name:   "PopulatedRange",
+				Inputs: []value.Any{value.NewInt(2), value.NewInt(5)},
+				Output: value.NewInt(9),
+			},
+		},
+	},
+	{
+		Name:        "ForRangeBreak",
+		Description: "Support for exiting a range loop early",
+		Parameters: []agnostic.Field{
+			{Name: "end", Type: types.BaseInt},
+			{Name: "limit", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Declare("result", value.NewInt(-1))
+
+			forRangeBody := body.ForRange("i", value.NewInt(0), value.NewId("end"))
+			ifBody := forRangeBody.If(value.NewCombined(value.NewCombined(value.NewId("i"), value.Multiply, value.NewId("i")), value.GreatThan, value.NewId("limit")))
+			ifBody.Assign(value.NewId("result"), value.NewId("i"))
+			ifBody.Break()
+
+			body.Return(value.NewId("result"))
+		},
+		Facts: []Fact{
+			{
+				Name:   "Break",
+				Inputs: []value.Any{value.NewInt(10), value.NewInt(20)},
+				Output: value.NewInt(5),
+			},
+			{
+				Name:   "NoBreak",
+				Inputs: []value.Any{value.NewInt(3), value.NewInt(20)},
+				Output: value.NewInt(-1),
+			},
+		},
+	},
+	{
+		Name:        "ForRangeContinue",
+		Description: "Support for skipping an iteration of a range loop",
+		Parameters: []agnostic.Field{
+			{Name: "end", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Declare("sum", value.NewInt(0))
+
+			forRangeBody := body.ForRange("i", value.NewInt(0), value.NewId("end"))
+			ifBody := forRangeBody.If(value.NewCombined(value.NewCombined(value.NewId("i"), value.Modulo, value.NewInt(2)), value.Equal, value.NewInt(0)))
+			ifBody.Continue()
+			forRangeBody.Assign(value.NewId("sum"), value.NewCombined(value.NewId("sum"), value.Add, value.NewId("i")))
+
+			body.Return(value.NewId("sum"))
+		},
+		Facts: []Fact{
+			{
+				Name:   "SumOfOdds",
+				Inputs: []value.Any{value.NewInt(6)},
+				Output: value.NewInt(9),
+			},
+		},
+	},
+}
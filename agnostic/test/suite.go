@@ -8,6 +8,7 @@ import (
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
 	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
 	"os"
+	"reflect"
 	"strings"
 )
 
@@ -33,6 +34,7 @@ func (s Suite) RemoveLimitations(limitations Limitations) (suite Suite, removed
 	removed = make([]RemovedCase, 0)
 
 	for _, c := range s {
+		c := c // Removed cases keep a pointer so each needs its own copy
 		justification, inLimitations := limitations[c.Name]
 		if !inLimitations {
 			suite = append(suite, c)
@@ -49,6 +51,10 @@ func (s Suite) GenerateAgnostic(implementation agnostic.Implementation) {
 	implementation.Model("TestModel", s.GetModelFields()...)
 
 	for _, c := range s {
+		if c.Dependencies != nil {
+			c.Dependencies(implementation)
+		}
+
 		if c.Returns == nil {
 			c.Generator(implementation.Method("TestModel", c.Name, c.Parameters...))
 		} else {
@@ -66,18 +72,21 @@ func (s Suite) GenerateTests(implementation Implementation) {
 
 func (s Suite) GetModelFields() []agnostic.Field {
 	fields := make([]agnostic.Field, 0)
-	fieldTypes := make(map[string]types.Any)
+	existingFields := make(map[string]agnostic.Field)
 	for _, c := range s {
 		for _, field := range c.ModelFields {
-			existingType, ok := fieldTypes[field.Name]
+			existingField, ok := existingFields[field.Name]
 			if ok {
-				// Ensure that the two fields have the same type
-				if existingType != field.Type {
+				// Ensure that the two fields have the same type and default
+				if existingField.Type != field.Type {
 					panic(errors.New("multiple requests for field \"" + field.Name + "\" with different types"))
 				}
+				if !reflect.DeepEqual(existingField.Default, field.Default) {
+					panic(errors.New("multiple requests for field \"" + field.Name + "\" with different defaults"))
+				}
 			} else {
 				fields = append(fields, field)
-				fieldTypes[field.Name] = field.Type
+				existingFields[field.Name] = field
 			}
 		}
 	}
@@ -89,23 +98,36 @@ var AllSuites = ComposeSuites(
 	ArraySuite,
 	MapSuite,
 	ForSuite,
+	ForRangeSuite,
+	WhileSuite,
 	IfSuite,
+	LogicSuite,
+	PrecedenceSuite,
+	SwitchSuite,
 	ValueSuite,
+	FunctionSuite,
+	CallSuite,
+	ConstructorSuite,
 )
 
 // A function that takes the given body implementation and the method that the
 // test will car
 type GenerateBodyFunc func(body agnostic.BodyImplementation)
 
+// A function that adds the code that a test case's method relies on (e.g.
+// functions or enums) to the given implementation
+type GenerateDependenciesFunc func(implementation agnostic.Implementation)
+
 // A method should be created
 type Case struct {
-	Name        string           // Name of the test case (must be unique)
-	Description string           // Describes what the test case is for
-	ModelFields []agnostic.Field // Fields that need to exist on TestModel for this test
-	Parameters  []agnostic.Field // Parameters that the generated test method will take in
-	Returns     types.Any        // The return type of the method or nil if it returns nothing
-	Generator   GenerateBodyFunc // Function that generates the method that the test will target
-	Facts       []Fact           // Facts about the Test
+	Name         string                   // Name of the test case (must be unique)
+	Description  string                   // Describes what the test case is for
+	ModelFields  []agnostic.Field         // Fields that need to exist on TestModel for this test
+	Parameters   []agnostic.Field         // Parameters that the generated test method will take in
+	Returns      types.Any                // The return type of the method or nil if it returns nothing
+	Dependencies GenerateDependenciesFunc // Function that generates code the method relies on or nil
+	Generator    GenerateBodyFunc         // Function that generates the method that the test will target
+	Facts        []Fact                   // Facts about the Test
 }
 
 // A change that happens to the model as a result of a method call
@@ -0,0 +1,124 @@
+package test
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+var SwitchSuite = Suite{
+	{
+		Name:        "Switch",
+		Description: "Support for switching on a value without falling through to the next case",
+		ModelFields: []agnostic.Field{
+			{Name: "SwitchResult", Type: types.BaseString},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "input", Type: types.BaseInt},
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			resultValue := value.NewOwnField(value.NewId("SwitchResult"))
+
+			caseBodies, defaultBody := body.Switch(value.NewId("input"), value.NewInt(1), value.NewInt(2))
+			caseBodies[0].Assign(resultValue, value.NewString("one"))
+			caseBodies[1].Assign(resultValue, value.NewString("two"))
+			defaultBody.Assign(resultValue, value.NewString("other"))
+		},
+		Facts: []Fact{
+			{
+				Name:   "FirstCase",
+				Inputs: []value.Any{value.NewInt(1)},
+				SideEffects: []SideEffect{
+					{FieldName: "SwitchResult", ExpectedValue: value.NewString("one")},
+				},
+			},
+			{
+				Name:   "LastCase",
+				Inputs: []value.Any{value.NewInt(2)},
+				SideEffects: []SideEffect{
+					{FieldName: "SwitchResult", ExpectedValue: value.NewString("two")},
+				},
+			},
+			{
+				Name:   "Default",
+				Inputs: []value.Any{value.NewInt(3)},
+				SideEffects: []SideEffect{
+					{FieldName: "SwitchResult", ExpectedValue: value.NewString("other")},
+				},
+			},
+		},
+	},
+	{
+		Name:        "SwitchEnum",
+		Description: "Support for switching on the value of an enum",
+		Parameters: []agnostic.Field{
+			{Name: "operation", Type: types.NewModel("Operation")},
+		},
+		Returns: types.BaseString,
+		Dependencies: func(implementation agnostic.Implementation) {
+			implementation.Enum("Operation", "Put", "Delete", "Clear")
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			caseBodies, defaultBody := body.Switch(
+				value.NewId("operation"),
+				value.NewEnumValue("Operation", "Put"),
+				value.NewEnumValue("Operation", "Delete"),
+			)
+			caseBodies[0].Return(value.NewString("put"))
+			caseBodies[1].Return(value.NewString("delete"))
+			defaultBody.Return(value.NewString("other"))
+		},
+		Facts: []Fact{
+			{
+				Name:   "Put",
+				Inputs: []value.Any{value.NewEnumValue("Operation", "Put")},
+				Output: value.NewString("put"),
+			},
+			{
+				Name:   "Delete",
+				Inputs: []value.Any{value.NewEnumValue("Operation", "Delete")},
+				Output: value.NewString("delete"),
+			},
+			{
+				Name:   "Clear",
+				Inputs: []value.Any{value.NewEnumValue("Operation", "Clear")},
+				Output: value.NewString("other"),
+			},
+		},
+	},
+	{
+		Name:        "SwitchBreak",
+		Description: "Support for breaking out of a case of a switch",
+		ModelFields: []agnostic.Field{
+			{Name: "BreakResult", Type: types.BaseInt},
+		},
+		Parameters: []agnostic.Field{
+			{Name: "input", Type: types.BaseInt},
+			{Name: "exitEarly", Type: types.BaseBool},
+		},
+		Generator: func(body agnostic.BodyImplementation) {
+			resultValue := value.NewOwnField(value.NewId("BreakResult"))
+
+			caseBodies, _ := body.Switch(value.NewId("input"), value.NewInt(1))
+			caseBodies[0].Assign(resultValue, value.NewInt(1))
+			caseBodies[0].If(value.NewId("exitEarly")).Break()
+			caseBodies[0].Assign(resultValue, value.NewInt(2))
+		},
+		Facts: []Fact{
+			{
+				Name:   "Break",
+				Inputs: []value.Any{value.NewInt(1), value.NewBool(true)},
+				SideEffects: []SideEffect{
+					{FieldName: "BreakResult", ExpectedValue: value.NewInt(1)},
+				},
+			},
+			{
+				Name:   "NoBreak",
+				Inputs: []value.Any{value.NewInt(1), value.NewBool(false)},
+				SideEffects: []SideEffect{
+					{FieldName: "BreakResult", ExpectedValue: value.NewInt(2)},
+				},
+			},
+		},
+	},
+}
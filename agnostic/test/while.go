@@ -0,0 +1,93 @@
+package test
+
+import (
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/types"
+	"github.com/JosephNaberhaus/go-delta-sync/agnostic/blocks/value"
+)
+
+var WhileSuite = Suite{
+	{
+		Name:        "While",
+		Description: "Support for while loops",
+		Parameters: []agnostic.Field{
+			{Name: "total", Type: types.BaseInt},
+			{Name: "step", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Declare("count", value.NewInt(0))
+			body.Declare("remaining", value.NewId("total"))
+
+			whileBody := body.While(value.NewCombined(value.NewId("remaining"), value.GreatThan, value.NewInt(0)))
+			whileBody.Assign(value.NewId("remaining"), value.NewCombined(value.NewId("remaining"), value.Subtract, value.NewId("step")))
+			whileBody.Assign(value.NewId("count"), value.NewCombined(value.NewId("count"), value.Add, value.NewInt(1)))
+
+			body.Return(value.NewId("count"))
+		},
+		Facts: []Fact{
+			{
+				Name:   "NoIterations",
+				Inputs: []value.Any{value.NewInt(0), value.NewInt(3)},
+				Output: value.NewInt(0),
+			},
+			{
+				Name:   "MultipleIterations",
+				Inputs: []value.Any{value.NewInt(10), value.NewInt(3)},
+				Output: value.NewInt(4),
+			},
+		},
+	},
+	{
+		Name:        "WhileBreak",
+		Description: "Support for exiting a while loop early",
+		Parameters: []agnostic.Field{
+			{Name: "limit", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Declare("count", value.NewInt(0))
+
+			whileBody := body.While(value.NewBool(true))
+			ifBody := whileBody.If(value.NewCombined(value.NewId("count"), value.GreatThanOrEqualTo, value.NewId("limit")))
+			ifBody.Break()
+			whileBody.Assign(value.NewId("count"), value.NewCombined(value.NewId("count"), value.Add, value.NewInt(1)))
+
+			body.Return(value.NewId("count"))
+		},
+		Facts: []Fact{
+			{
+				Name:   "Break",
+				Inputs: []value.Any{value.NewInt(3)},
+				Output: value.NewInt(3),
+			},
+		},
+	},
+	{
+		Name:        "WhileContinue",
+		Description: "Support for skipping an iteration of a while loop",
+		Parameters: []agnostic.Field{
+			{Name: "end", Type: types.BaseInt},
+		},
+		Returns: types.BaseInt,
+		Generator: func(body agnostic.BodyImplementation) {
+			body.Declare("i", value.NewInt(0))
+			body.Declare("sum", value.NewInt(0))
+
+			whileBody := body.While(value.NewCombined(value.NewId("i"), value.LessThan, value.NewId("end")))
+			whileBody.Assign(value.NewId("i"), value.NewCombined(value.NewId("i"), value.Add, value.NewInt(1)))
+			ifBody := whileBody.If(value.NewCombined(value.NewCombined(value.NewId("i"), value.Modulo, value.NewInt(3)), value.Equal, value.NewInt(0)))
+			ifBody.Continue()
+			whileBody.Assign(value.NewId("sum"), value.NewCombined(value.NewId("sum"), value.Add, value.NewId("i")))
+
+			body.Return(value.NewId("sum"))
+		},
+		Facts: []Fact{
+			{
+				Name:   "SkipMultiplesOfThree",
+				Inputs: []value.Any{value.NewInt(6)},
+				Output: value.NewInt(12),
+			},
+		},
+	},
+}